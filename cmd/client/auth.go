@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gSSH/pb"
+	"gSSH/pkg/auth"
+	"net"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/term"
+)
+
+// tokenCredentials attaches the session token to every RPC once the client
+// has authenticated. It is empty until then.
+type tokenCredentials struct {
+	mux   sync.Mutex
+	token string
+}
+
+func (c *tokenCredentials) set(token string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.token = token
+}
+
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.token == "" {
+		return nil, nil
+	}
+	return map[string]string{auth.SessionTokenHeader: c.token}, nil
+}
+
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// bearerToken sends an API token with every RPC
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{auth.AuthorizationHeader: "Bearer " + string(t)}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// keySigner signs the authentication challenge with one SSH key
+type keySigner struct {
+	key  ssh.PublicKey
+	sign func(data []byte) (*ssh.Signature, error)
+}
+
+// loadSigners returns the keys to try, either the private key given by
+// --identity or every key of the running ssh-agent
+func loadSigners(identityFile string) ([]keySigner, error) {
+	if identityFile != "" {
+		signer, err := loadPrivateKey(identityFile)
+		if err != nil {
+			return nil, err
+		}
+		return []keySigner{{key: signer.PublicKey(), sign: func(data []byte) (*ssh.Signature, error) {
+			return signer.Sign(nil, data)
+		}}}, nil
+	}
+
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, fmt.Errorf("no --identity given and no ssh-agent running")
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent: %v", err)
+	}
+
+	sshAgent := agent.NewClient(conn)
+	keys, err := sshAgent.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ssh-agent keys: %v", err)
+	}
+
+	signers := make([]keySigner, 0, len(keys))
+	for _, key := range keys {
+		signers = append(signers, keySigner{key: key, sign: func(data []byte) (*ssh.Signature, error) {
+			// Plain ssh-rsa signatures use SHA-1, ask for SHA-256 instead
+			if key.Type() == ssh.KeyAlgoRSA {
+				return sshAgent.SignWithFlags(key, data, agent.SignatureFlagRsaSha256)
+			}
+			return sshAgent.Sign(key, data)
+		}})
+	}
+	return signers, nil
+}
+
+// loadPrivateKey reads an SSH private key, asking for its passphrase if it has one
+func loadPrivateKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identity: %v", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if _, ok := err.(*ssh.PassphraseMissingError); ok {
+		fmt.Fprintf(os.Stderr, "Enter passphrase for %s: ", path)
+		passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase: %v", err)
+		}
+		return ssh.ParsePrivateKeyWithPassphrase(data, passphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identity: %v", err)
+	}
+	return signer, nil
+}
+
+// authenticate proves the user holds one of the keys and stores the session
+// token the server returns in creds
+func authenticate(client pb.TerminalServiceClient, creds *tokenCredentials, userName, identityFile string) error {
+	signers, err := loadSigners(identityFile)
+	if err != nil {
+		return err
+	}
+
+	for _, signer := range signers {
+		token, err := authenticateWith(client, userName, signer)
+		if err != nil {
+			continue
+		}
+		creds.set(token)
+		return nil
+	}
+	return fmt.Errorf("no key was accepted for %s", userName)
+}
+
+func authenticateWith(client pb.TerminalServiceClient, userName string, signer keySigner) (string, error) {
+	stream, err := client.Authenticate(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(&pb.AuthRequest{Payload: &pb.AuthRequest_Start{Start: &pb.AuthStart{
+		User:      userName,
+		PublicKey: signer.key.Marshal(),
+	}}})
+	if err != nil {
+		return "", err
+	}
+
+	res, err := stream.Recv()
+	if err != nil {
+		return "", err
+	}
+
+	// The server derives the same binding from its end of the connection
+	binding, err := auth.ChannelBinding(stream.Context())
+	if err != nil {
+		return "", err
+	}
+	signature, err := signer.sign(auth.ChallengeData(userName, res.GetNonce(), binding))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign challenge: %v", err)
+	}
+	if err := stream.Send(&pb.AuthRequest{Payload: &pb.AuthRequest_Signature{Signature: ssh.Marshal(signature)}}); err != nil {
+		return "", err
+	}
+
+	res, err = stream.Recv()
+	if err != nil {
+		return "", err
+	}
+	return res.GetToken().GetToken(), nil
+}
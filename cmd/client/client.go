@@ -1,95 +1,243 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"crypto/tls"
-	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	env "gSSH/cmd"
 	"gSSH/pb"
+	"gSSH/pkg/pki"
+	"gSSH/pkg/tracing"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"github.com/creack/pty"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"golang.org/x/term"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
 
 var environment = env.NewEnv()
 
+// flushTracing exports the buffered spans, main sets it up once tracing is
+var flushTracing = func() {}
+
+// fatalf logs and exits like log.Fatalf, flushing the spans first since
+// deferred calls don't run on exit
+func fatalf(format string, args ...any) {
+	flushTracing()
+	log.Fatalf(format, args...)
+}
+
 func init() {
 	// Set default values
 	viper.SetDefault("port", environment.ServerPort)
 	viper.SetDefault("id", "")
+	viper.SetDefault("exec", "")
+	viper.SetDefault("signal", "")
+	viper.SetDefault("observe", false)
+	viper.SetDefault("shared", false)
+	viper.SetDefault("viewers", []string{})
+	viper.SetDefault("cert", "")
+	viper.SetDefault("key", "")
+	viper.SetDefault("ca", "")
+	viper.SetDefault("bootstrap-psk", "")
+	viper.SetDefault("fingerprint", "")
+	viper.SetDefault("known-hosts", defaultKnownHostsFile())
+	viper.SetDefault("user", "")
+	viper.SetDefault("identity", "")
+	viper.SetDefault("token", "")
+	viper.SetDefault("speed", 1.0)
+	viper.SetDefault("idle-limit", time.Duration(0))
 
 	// Command-line flags
 	pflag.Int("port", environment.ServerPort, "Port to run the TCP connection")
 	pflag.String("id", "", "Session ID")
-
-	pflag.Parse()
+	pflag.String("exec", "", "Run a single command without a session and exit with its code")
+	pflag.String("signal", "", "Send a signal (e.g. SIGTERM) to the foreground job of the session given by --id and exit")
+	pflag.Bool("observe", false, "Attach to the session read-only")
+	pflag.Bool("shared", false, "Let other clients attach as writers too, when creating a session")
+	pflag.StringSlice("viewers", nil, "Other users allowed to observe the session, when creating it")
+	pflag.String("cert", "", "Client certificate for mutual TLS")
+	pflag.String("key", "", "Private key of the client certificate")
+	pflag.String("ca", "", "CA bundle to verify the server with, instead of fetching and pinning its certificate")
+	pflag.String("known-hosts", defaultKnownHostsFile(), "File of pinned server certificates")
+	pflag.String("bootstrap-psk", "", "Pre-shared key the trust bundle of a new server is signed with")
+	pflag.String("fingerprint", "", "Expected fingerprint of the trust bundle of a new server")
+	pflag.String("user", "", "Authenticate as this user with an SSH key")
+	pflag.String("identity", "", "SSH private key to authenticate with, instead of the keys of ssh-agent")
+	pflag.String("token", "", "API token to authenticate with")
+	pflag.Float64("speed", 1, "Replay recordings this many times faster")
+	pflag.Duration("idle-limit", 0, "Cut pauses of replayed recordings to this long")
 
 	// Bind the flags to viper
 	viper.BindPFlag("port", pflag.Lookup("port"))
 	viper.BindPFlag("id", pflag.Lookup("id"))
+	viper.BindPFlag("exec", pflag.Lookup("exec"))
+	viper.BindPFlag("signal", pflag.Lookup("signal"))
+	viper.BindPFlag("observe", pflag.Lookup("observe"))
+	viper.BindPFlag("shared", pflag.Lookup("shared"))
+	viper.BindPFlag("viewers", pflag.Lookup("viewers"))
+	viper.BindPFlag("cert", pflag.Lookup("cert"))
+	viper.BindPFlag("key", pflag.Lookup("key"))
+	viper.BindPFlag("ca", pflag.Lookup("ca"))
+	viper.BindPFlag("known-hosts", pflag.Lookup("known-hosts"))
+	viper.BindPFlag("bootstrap-psk", pflag.Lookup("bootstrap-psk"))
+	viper.BindPFlag("fingerprint", pflag.Lookup("fingerprint"))
+	viper.BindPFlag("user", pflag.Lookup("user"))
+	viper.BindPFlag("identity", pflag.Lookup("identity"))
+	viper.BindPFlag("token", pflag.Lookup("token"))
+	viper.BindPFlag("speed", pflag.Lookup("speed"))
+	viper.BindPFlag("idle-limit", pflag.Lookup("idle-limit"))
 
 	// Environment variables
 	viper.BindEnv("id", "SESSION_ID")
 	viper.BindEnv("port", "SERVER_PORT")
+	viper.BindEnv("cert", "GSSH_CERT")
+	viper.BindEnv("key", "GSSH_KEY")
+	viper.BindEnv("ca", "GSSH_CA")
+	viper.BindEnv("known-hosts", "GSSH_KNOWN_HOSTS")
+	viper.BindEnv("bootstrap-psk", "GSSH_BOOTSTRAP_PSK")
+	viper.BindEnv("fingerprint", "GSSH_FINGERPRINT")
+	viper.BindEnv("user", "GSSH_USER")
+	viper.BindEnv("identity", "GSSH_IDENTITY")
+	viper.BindEnv("token", "GSSH_TOKEN")
 }
 
-func fetchCertificate(url string) ([]byte, error) { // Perform a GET request to fetch the certificate
-	resp, err := http.Get("http://" + url + "/cert")
+// fetchBundle downloads the trust bundle of the server. The HTTPS
+// certificate can't be verified before the client trusts the server, which
+// is what the bundle is for, so the bundle itself is checked afterwards.
+func fetchBundle(ctx context.Context, address string) (_ *pki.SignedBundle, err error) {
+	ctx, span := tracing.Start(ctx, "trust.fetch_bundle")
+	defer func() { tracing.End(span, err) }()
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+address+"/bundle", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch cert: %v", err)
+		return nil, fmt.Errorf("failed to fetch trust bundle: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch cert: server returned %v", resp.Status)
+		return nil, fmt.Errorf("failed to fetch trust bundle: server returned %v", resp.Status)
 	}
 
-	cert, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read cert body: %v", err)
+	var bundle pki.SignedBundle
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("failed to read trust bundle: %v", err)
 	}
-	return cert, nil
+	return &bundle, nil
 }
 
 func main() {
+	pflag.Parse()
+
+	// Recordings are replayed locally, without a server
+	if pflag.Arg(0) == "replay" {
+		if pflag.Arg(1) == "" {
+			log.Fatalf("replay requires the recording file")
+		}
+		if err := replayRecording(pflag.Arg(1), viper.GetFloat64("speed"), viper.GetDuration("idle-limit")); err != nil {
+			log.Fatalf("failed to replay recording: %v", err)
+		}
+		return
+	}
+
 	port := viper.GetInt("port")
 	sessionID := viper.GetString("id")
+	execCommand := viper.GetString("exec")
+	signalName := viper.GetString("signal")
+
+	mode := pb.AttachMode_WRITER
+	if viper.GetBool("observe") {
+		mode = pb.AttachMode_OBSERVER
+	}
 
-	address := fmt.Sprintf(":%d", port)
-	fmt.Printf("Starting client on address: %s...\n", address)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "gssh-client",
+		Endpoint:    environment.TracingEndpoint,
+		Insecure:    environment.TracingInsecure,
+		SampleRatio: environment.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	// Spans are buffered, so they are flushed before every exit
+	flushTracing = func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdownTracing(ctx)
+	}
+	defer flushTracing()
+
+	// Everything up to attaching to the session is traced as one connect,
+	// to see whether time goes to fetching the trust bundle, the TLS
+	// handshake or starting the shell
+	ctx, connectSpan := tracing.Start(context.Background(), "connect")
+
+	// Exec mode keeps stdout clean for the remote command's output
+	if execCommand == "" {
+		address := fmt.Sprintf(":%d", port)
+		fmt.Printf("Starting client on address: %s...\n", address)
+	}
 
 	certPortStr := strconv.Itoa(environment.ServerCertPort)
 	certAddress := environment.ServerAddress + ":" + certPortStr
+	TCPaddress := fmt.Sprintf("%s:%d", environment.ServerAddress, port)
 
-	cert, err := fetchCertificate(certAddress)
+	tlsConfig, err := serverTLSConfig(ctx, TCPaddress, certAddress, trustOptions{
+		caFile:         viper.GetString("ca"),
+		knownHostsFile: viper.GetString("known-hosts"),
+		psk:            viper.GetString("bootstrap-psk"),
+		fingerprint:    viper.GetString("fingerprint"),
+	})
 	if err != nil {
-		log.Fatalf("failed to fetch cert: %v", err)
+		fatalf("failed to trust server: %v", err)
 	}
 
-	// Create a certificate pool
-	certPool := x509.NewCertPool()
-	if ok := certPool.AppendCertsFromPEM(cert); !ok {
-		log.Fatalf("failed to append cert to pool: invalid PEM format or empty certificate")
+	// Present a client certificate when the server asks for mutual TLS
+	if certFile, keyFile := viper.GetString("cert"), viper.GetString("key"); certFile != "" || keyFile != "" {
+		clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			fatalf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
 	}
 
-	creds := credentials.NewTLS(&tls.Config{RootCAs: certPool})
-	TCPaddress := fmt.Sprintf("%s:%d", environment.ServerAddress, port)
+	creds := tracedCredentials{TransportCredentials: credentials.NewTLS(tlsConfig), ctx: ctx}
 
-	socket, err := grpc.NewClient(
-		TCPaddress,
+	tokenCreds := &tokenCredentials{}
+	options := []grpc.DialOption{
 		grpc.WithTransportCredentials(creds),
-	)
+		grpc.WithPerRPCCredentials(tokenCreds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if apiToken := viper.GetString("token"); apiToken != "" {
+		options = append(options, grpc.WithPerRPCCredentials(bearerToken(apiToken)))
+	}
+
+	socket, err := grpc.NewClient(TCPaddress, options...)
 	if err != nil {
 		panic(err)
 	}
@@ -97,15 +245,53 @@ func main() {
 
 	client := pb.NewTerminalServiceClient(socket)
 
-	sessionRes, err := client.RequestSession(context.Background(), &pb.SessionRequest{Id: &sessionID})
+	if userName := viper.GetString("user"); userName != "" {
+		if err := authenticate(client, tokenCreds, userName, viper.GetString("identity")); err != nil {
+			fatalf("authentication failed: %v", err)
+		}
+	}
+
+	if execCommand != "" {
+		connectSpan.End()
+		code := runExec(client, execCommand)
+		flushTracing()
+		os.Exit(code)
+	}
+
+	if pflag.Arg(0) == "sessions" {
+		listSessions(client)
+		return
+	}
+
+	if pflag.Arg(0) == "drain" || pflag.Arg(0) == "undrain" {
+		drainServer(client, pflag.Arg(0) == "drain")
+		return
+	}
+
+	if pflag.Arg(0) == "terminate" {
+		terminateSession(client, sessionID)
+		return
+	}
+
+	if signalName != "" {
+		signalSession(client, sessionID, signalName)
+		return
+	}
+
+	sessionRes, err := client.RequestSession(ctx, &pb.SessionRequest{
+		Id:          &sessionID,
+		Mode:        mode,
+		SharedWrite: viper.GetBool("shared"),
+		Viewers:     viper.GetStringSlice("viewers"),
+	})
 	if err != nil {
-		log.Fatalf("Failed to request session: %v", err)
+		fatalf("Failed to request session: %v", err)
 	}
 
 	fmt.Printf("SessionID: %s\n", sessionRes.Id)
 
 	if sessionRes.SessionStatus != pb.SessionStatus_AVAILABLE {
-		log.Fatalf("Session not available: %v", sessionRes.SessionStatus)
+		fatalf("Session not available: %v", sessionRes.SessionStatus)
 	}
 
 	// Update sessionID with the ID received from the server if it was generated there
@@ -113,27 +299,94 @@ func main() {
 		sessionID = sessionRes.Id
 	}
 
-	stream, err := client.ExecuteCommand(context.Background())
+	stream, err := client.ExecuteCommand(ctx)
 	if err != nil {
 		panic(err)
 	}
+	connectSpan.End()
 	fmt.Println("Client connected with TLS!")
 
+	// gRPC streams don't allow concurrent sends, and both stdin and resize
+	// events are written from their own goroutines
+	var sendMux sync.Mutex
+	send := func(req *pb.CommandRequest) error {
+		sendMux.Lock()
+		defer sendMux.Unlock()
+		req.SessionId = sessionID
+		req.Mode = mode
+		return stream.Send(req)
+	}
+
+	// Observers are read-only, they never send input, window sizes or
+	// signals, just an empty message to attach
+	observer := mode == pb.AttachMode_OBSERVER
+	if observer {
+		if err := send(&pb.CommandRequest{}); err != nil {
+			fatalf("failed to attach to session: %v", err)
+		}
+		fmt.Println("Attached read-only, type ~. to detach")
+	}
+
+	// Report the window size on connect and every time the local terminal is resized
+	if !observer {
+		winch := make(chan os.Signal, 1)
+		signal.Notify(winch, syscall.SIGWINCH)
+		defer signal.Stop(winch)
+		go func() {
+			for range winch {
+				if size := windowSize(); size != nil {
+					if err := send(&pb.CommandRequest{Payload: &pb.CommandRequest_Resize{Resize: size}}); err != nil {
+						return
+					}
+				}
+			}
+		}()
+		winch <- syscall.SIGWINCH
+	}
+
+	// Put the local terminal into raw mode so every keystroke, including
+	// control sequences such as Ctrl-C or arrow keys, reaches the remote PTY
+	restoreTerminal := makeRaw()
+	defer restoreTerminal()
+
+	// Closing our side of the stream detaches from the session, which keeps
+	// running on the server
+	var detached atomic.Bool
+	detach := func() {
+		detached.Store(true)
+		sendMux.Lock()
+		_ = stream.CloseSend()
+		sendMux.Unlock()
+	}
+
+	// Forward the signals received locally to the foreground job of the
+	// remote session instead of tearing the session down. Observers can't
+	// signal the session, so they detach instead
 	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGTSTP)
+	defer signal.Stop(sigs)
 
 	go func() {
-		sig := <-sigs
-		fmt.Printf("\nReceived signal: %s. Shutting down...\n", sig)
-
-		if _, err := client.MakeSessionAvailable(context.Background(), &pb.SessionRequest{Id: &sessionID}); err != nil {
-			log.Fatalf("failed to make session available: %v", err)
+		for sig := range sigs {
+			remoteSignal, ok := signals[sig]
+			if !ok {
+				continue
+			}
+			if observer {
+				detach()
+				return
+			}
+			if err := send(&pb.CommandRequest{Payload: &pb.CommandRequest_Signal{Signal: remoteSignal}}); err != nil {
+				return
+			}
 		}
-		os.Exit(0)
 	}()
 
-	// Anonymous function to receive the responses
-	done := make(chan bool)
+	// Anonymous function to receive the responses. The remote exit status is
+	// kept so the client can exit with the same code. It owns done, which
+	// nothing else sends on, so closing it can't race with another send.
+	done := make(chan error, 1)
+	var exitStatus *pb.ExitStatus
 	go func() {
 		for {
 			response, err := stream.Recv()
@@ -141,26 +394,135 @@ func main() {
 				break
 			}
 			if err != nil {
-				panic(err)
+				done <- err
+				return
 			}
 
-			fmt.Printf(response.Output)
+			switch payload := response.Payload.(type) {
+			case *pb.CommandResponse_Output:
+				os.Stdout.Write(payload.Output)
+			case *pb.CommandResponse_Exit:
+				exitStatus = payload.Exit
+			case *pb.CommandResponse_Notice:
+				fmt.Fprintf(os.Stderr, "\r\n[gSSH] %s\r\n", payload.Notice)
+			}
 		}
 		close(done)
 	}()
 
-	scanner := bufio.NewScanner(os.Stdin)
+	// Forward raw stdin bytes to the remote PTY, a failure to send ends the
+	// client as well
+	inputErr := make(chan error, 1)
+	go func() {
+		escape := newEscapeFilter()
+		buf := make([]byte, 1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				input, escaped := escape.filter(buf[:n])
+				if len(input) > 0 && !observer {
+					if err := send(&pb.CommandRequest{Payload: &pb.CommandRequest_Input{Input: input}}); err != nil {
+						inputErr <- fmt.Errorf("error sending input: %v", err)
+						return
+					}
+				}
+				if escaped {
+					detach()
+					return
+				}
+			}
+			if err != nil {
+				sendMux.Lock()
+				_ = stream.CloseSend()
+				sendMux.Unlock()
+				return
+			}
+		}
+	}()
+
+	select {
+	case err = <-done:
+	case err = <-inputErr:
+	}
+	restoreTerminal()
+	if err != nil {
+		fatalf("connection closed: %v", err)
+	}
 
-	for scanner.Scan() {
-		command := scanner.Text()
-		err := stream.Send(&pb.CommandRequest{
-			Command:   command,
-			SessionId: sessionID,
-		})
-		if err != nil {
-			log.Fatalf("error sending command: %v", err)
+	if detached.Load() {
+		fmt.Printf("\nDetached from session %s, reattach with --id=%s\n", sessionID, sessionID)
+		return
+	}
+
+	if exitStatus != nil {
+		if exitStatus.Signal != nil {
+			fmt.Printf("Remote shell terminated by %s\n", exitStatus.GetSignal())
 		}
+		flushTracing()
+		os.Exit(int(exitStatus.Code))
+	}
+}
+
+// signals maps the local signals that are forwarded to the remote session
+var signals = map[os.Signal]pb.Signal{
+	syscall.SIGINT:  pb.Signal_SIGINT,
+	syscall.SIGTERM: pb.Signal_SIGTERM,
+	syscall.SIGHUP:  pb.Signal_SIGHUP,
+	syscall.SIGTSTP: pb.Signal_SIGTSTP,
+}
+
+// signalSession sends a single signal to an existing session
+func signalSession(client pb.TerminalServiceClient, sessionID, signalName string) {
+	remoteSignal, ok := pb.Signal_value[strings.ToUpper(signalName)]
+	if !ok || pb.Signal(remoteSignal) == pb.Signal_SIGNAL_UNSPECIFIED {
+		fatalf("unsupported signal: %s", signalName)
+	}
+	if sessionID == "" {
+		fatalf("--signal requires the --id of the session")
+	}
+
+	res, err := client.SignalSession(context.Background(), &pb.SignalRequest{
+		Id:     sessionID,
+		Signal: pb.Signal(remoteSignal),
+	})
+	if err != nil {
+		fatalf("failed to signal session: %v", err)
+	}
+	fmt.Printf("Session %s: %v\n", res.Id, res.SessionStatus)
+}
+
+// windowSize reads the size of the local terminal, or returns nil when stdin
+// is not a terminal
+func windowSize() *pb.WindowSize {
+	size, err := pty.GetsizeFull(os.Stdin)
+	if err != nil {
+		return nil
+	}
+
+	return &pb.WindowSize{
+		Rows:   uint32(size.Rows),
+		Cols:   uint32(size.Cols),
+		Width:  uint32(size.X),
+		Height: uint32(size.Y),
+	}
+}
+
+// makeRaw switches stdin into raw mode when it is a terminal and returns a
+// function that restores the previous state. The returned function is safe
+// to call more than once.
+func makeRaw() func() {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return func() {}
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		fatalf("failed to set terminal to raw mode: %v", err)
 	}
 
-	<-done
+	var once sync.Once
+	return func() {
+		once.Do(func() { _ = term.Restore(fd, oldState) })
+	}
 }
@@ -0,0 +1,41 @@
+package main
+
+// escapeFilter watches the keystrokes sent to the session for the "~."
+// sequence typed at the beginning of a line, the same escape ssh uses,
+// which detaches the client while leaving the session running.
+// Typing "~~" sends a single "~".
+type escapeFilter struct {
+	lineStart bool // The last keystroke ended a line
+	pending   bool // A "~" was typed at the beginning of a line
+}
+
+func newEscapeFilter() *escapeFilter {
+	return &escapeFilter{lineStart: true}
+}
+
+// filter returns the keystrokes to forward and whether to detach
+func (f *escapeFilter) filter(input []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(input))
+	for _, b := range input {
+		if f.pending {
+			f.pending = false
+			switch b {
+			case '.':
+				return out, true
+			case '~':
+				out = append(out, '~')
+				f.lineStart = false
+				continue
+			default:
+				out = append(out, '~')
+			}
+		} else if f.lineStart && b == '~' {
+			f.pending = true
+			continue
+		}
+
+		out = append(out, b)
+		f.lineStart = b == '\r' || b == '\n'
+	}
+	return out, false
+}
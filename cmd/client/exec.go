@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"gSSH/pb"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// runExec runs a single shell command on the server without a PTY, copying
+// its stdout and stderr to the local ones. It returns the remote exit code.
+func runExec(client pb.TerminalServiceClient, command string) int {
+	stream, err := client.Exec(context.Background())
+	if err != nil {
+		fatalf("failed to start exec: %v", err)
+	}
+
+	if err := stream.Send(&pb.ExecRequest{Payload: &pb.ExecRequest_Command{
+		Command: &pb.ExecCommand{Shell: command},
+	}}); err != nil {
+		fatalf("failed to send command: %v", err)
+	}
+
+	// Only forward stdin when something is piped into the client, otherwise
+	// the remote command gets an empty stdin right away
+	go func() {
+		defer stream.CloseSend()
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			return
+		}
+
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				if err := stream.Send(&pb.ExecRequest{Payload: &pb.ExecRequest_Stdin{Stdin: buf[:n]}}); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			fatalf("exec stream closed without an exit status")
+		}
+		if err != nil {
+			fatalf("exec failed: %v", err)
+		}
+
+		switch payload := response.Payload.(type) {
+		case *pb.ExecResponse_Output:
+			if payload.Output.Stream == pb.OutputStream_STDERR {
+				os.Stderr.Write(payload.Output.Data)
+			} else {
+				os.Stdout.Write(payload.Output.Data)
+			}
+		case *pb.ExecResponse_Exit:
+			return int(payload.Exit.Code)
+		}
+	}
+}
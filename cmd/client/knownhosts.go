@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"gSSH/pkg/pki"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// knownHosts pins the certificate of each server the user accepted, in a
+// file with one "<address> <fingerprint>" line per server, like ssh does
+type knownHosts struct {
+	path  string
+	hosts map[string]string
+}
+
+func loadKnownHosts(path string) (*knownHosts, error) {
+	k := &knownHosts{path: path, hosts: make(map[string]string)}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known hosts: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && !strings.HasPrefix(fields[0], "#") {
+			k.hosts[fields[0]] = fields[1]
+		}
+	}
+	return k, scanner.Err()
+}
+
+// add pins the fingerprint of a server and saves it
+func (k *knownHosts) add(address, fingerprint string) error {
+	if err := os.MkdirAll(filepath.Dir(k.path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(k.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "%s %s\n", address, fingerprint); err != nil {
+		return err
+	}
+	k.hosts[address] = fingerprint
+	return nil
+}
+
+// defaultKnownHostsFile is ~/.gssh/known_hosts
+func defaultKnownHostsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".gssh_known_hosts"
+	}
+	return filepath.Join(home, ".gssh", "known_hosts")
+}
+
+// trustOptions are the ways the user can tell the client which server to trust
+type trustOptions struct {
+	caFile         string
+	knownHostsFile string
+	psk            string // Key the trust bundle is signed with
+	fingerprint    string // Expected fingerprint of the trust bundle
+}
+
+// serverTLSConfig decides how to trust the server. With a CA bundle the
+// certificate is verified as usual. Otherwise it must match the one pinned
+// in known hosts. An unknown server's trust bundle is fetched and pinned once
+// its signature or fingerprint checks out, or the user accepted it.
+func serverTLSConfig(ctx context.Context, address, certAddress string, options trustOptions) (*tls.Config, error) {
+	if options.caFile != "" {
+		ca, err := os.ReadFile(options.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA: %v", err)
+		}
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(ca); !ok {
+			return nil, fmt.Errorf("no certificate found in %s", options.caFile)
+		}
+		return &tls.Config{RootCAs: certPool}, nil
+	}
+
+	knownHostsFile := options.knownHostsFile
+	hosts, err := loadKnownHosts(knownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, ok := hosts.hosts[address]
+	if !ok {
+		pinned, err = bootstrapTrust(ctx, address, certAddress, options)
+		if err != nil {
+			return nil, err
+		}
+		if err := hosts.add(address, pinned); err != nil {
+			return nil, fmt.Errorf("failed to save known host: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Added %s to %s\n", address, knownHostsFile)
+	}
+
+	// The pin replaces the usual chain and name verification. gRPC buries
+	// handshake errors in its own, so the warning is printed right away.
+	var warnOnce sync.Once
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("server sent no certificate")
+			}
+			if !matchesPin(state.PeerCertificates, pinned) {
+				actual := pki.Fingerprint(state.PeerCertificates[0].Raw)
+				warnOnce.Do(func() { printHostChanged(address, pinned, actual, knownHostsFile) })
+				return fmt.Errorf("certificate of %s doesn't match the pinned one", address)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// matchesPin reports whether the server certificate is the pinned one, or
+// was issued by the pinned CA, which keeps renewed certificates trusted
+func matchesPin(chain []*x509.Certificate, pinned string) bool {
+	for i, cert := range chain {
+		if pki.Fingerprint(cert.Raw) != pinned {
+			continue
+		}
+		if i == 0 {
+			return true
+		}
+
+		roots := x509.NewCertPool()
+		roots.AddCert(cert)
+		intermediates := x509.NewCertPool()
+		for _, intermediate := range chain[1:i] {
+			intermediates.AddCert(intermediate)
+		}
+		_, err := chain[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+		return err == nil
+	}
+	return false
+}
+
+// bootstrapTrust fetches the trust bundle of an unknown server and returns
+// the fingerprint to pin, once the bundle is signed with the pre-shared key,
+// matches the expected fingerprint or the user accepted it
+func bootstrapTrust(ctx context.Context, address, certAddress string, options trustOptions) (string, error) {
+	signed, err := fetchBundle(ctx, certAddress)
+	if err != nil {
+		return "", err
+	}
+
+	var bundle *pki.TrustBundle
+	if options.psk != "" {
+		bundle, err = signed.Verify([]byte(options.psk))
+	} else {
+		bundle, err = signed.Open()
+	}
+	if err != nil {
+		return "", err
+	}
+	certFingerprint := bundle.Fingerprint
+
+	if options.fingerprint != "" {
+		if certFingerprint != options.fingerprint {
+			return "", fmt.Errorf("trust bundle of %s has fingerprint %s, expected %s", address, certFingerprint, options.fingerprint)
+		}
+		return certFingerprint, nil
+	}
+	if options.psk != "" {
+		return certFingerprint, nil
+	}
+
+	// Ask on the terminal, stdin may carry input for the remote command
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("the authenticity of %s (%s) can't be established and there is no terminal to confirm it, connect interactively once or use --fingerprint, --bootstrap-psk or --ca", address, certFingerprint)
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "The authenticity of host '%s' can't be established.\n", address)
+	fmt.Fprintf(tty, "Certificate fingerprint is %s.\n", certFingerprint)
+	fmt.Fprint(tty, "Are you sure you want to continue connecting (yes/no)? ")
+
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+		return "", fmt.Errorf("host certificate not accepted")
+	}
+	return certFingerprint, nil
+}
+
+func printHostChanged(address, pinned, actual, knownHostsFile string) {
+	fmt.Fprintf(os.Stderr, `@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@
+@    WARNING: REMOTE HOST IDENTIFICATION HAS CHANGED!     @
+@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@
+IT IS POSSIBLE THAT SOMEONE IS DOING SOMETHING NASTY!
+Someone could be eavesdropping on you right now (man-in-the-middle attack),
+or the server certificate was replaced.
+The certificate of %s is %s,
+but %s is pinned in %s.
+Remove the line of %s from it if you are sure the new certificate is legitimate.
+`, address, actual, pinned, knownHostsFile, address)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/x509"
+	"gSSH/pkg/pki"
+	"testing"
+)
+
+// newChain issues a server certificate and returns the chain the server
+// presents, leaf first, with its CA
+func newChain(t *testing.T, ca *pki.CA) []*x509.Certificate {
+	t.Helper()
+
+	cert, err := ca.IssueServer([]string{"localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := make([]*x509.Certificate, len(cert.Certificate))
+	for i, der := range cert.Certificate {
+		chain[i], err = x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return chain
+}
+
+func TestMatchesPin(t *testing.T) {
+	ca, err := pki.NewCA("gSSH test CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, err := pki.NewCA("gSSH other CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := newChain(t, ca)
+	renewed := newChain(t, ca)
+	foreign := newChain(t, otherCA)
+	leafPin := pki.Fingerprint(chain[0].Raw)
+	caPin := pki.Fingerprint(ca.Cert.Raw)
+
+	for _, test := range []struct {
+		name   string
+		chain  []*x509.Certificate
+		pinned string
+		want   bool
+	}{
+		{"leaf pin", chain, leafPin, true},
+		{"leaf pin without the CA", chain[:1], leafPin, true},
+		{"leaf pin after renewal", renewed, leafPin, false},
+		{"CA pin", chain, caPin, true},
+		{"CA pin after renewal", renewed, caPin, true},
+		{"CA pin without the CA in the chain", chain[:1], caPin, false},
+		{"chain from another CA", foreign, caPin, false},
+		{"another CA's leaf with the pinned CA appended", []*x509.Certificate{foreign[0], ca.Cert}, caPin, false},
+		{"no certificates", nil, leafPin, false},
+	} {
+		if got := matchesPin(test.chain, test.pinned); got != test.want {
+			t.Errorf("%s: matchesPin = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
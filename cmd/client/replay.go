@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"gSSH/pkg/session"
+	"os"
+	"time"
+)
+
+// replayRecording plays the output of an asciicast recording back on stdout,
+// speed times faster than it was recorded. Pauses are cut to idleLimit, when
+// set, so a session left alone doesn't stall the replay.
+func replayRecording(path string, speed float64, idleLimit time.Duration) error {
+	if speed <= 0 {
+		return fmt.Errorf("speed must be positive")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s is empty", path)
+	}
+	var header session.CastHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return fmt.Errorf("invalid asciicast header: %w", err)
+	}
+	if header.Version != 2 {
+		return fmt.Errorf("unsupported asciicast version %d", header.Version)
+	}
+
+	var last float64
+	start := time.Now()
+	var skipped time.Duration // Idle time cut from the replay so far
+	for line := 2; scanner.Scan(); line++ {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			return fmt.Errorf("invalid event on line %d", line)
+		}
+		elapsed, ok1 := event[0].(float64)
+		kind, ok2 := event[1].(string)
+		data, ok3 := event[2].(string)
+		if !ok1 || !ok2 || !ok3 {
+			return fmt.Errorf("invalid event on line %d", line)
+		}
+
+		if pause := time.Duration((elapsed - last) * float64(time.Second)); idleLimit > 0 && pause > idleLimit {
+			skipped += pause - idleLimit
+		}
+		last = elapsed
+
+		// Input is echoed by the PTY and resizes can't be applied to the
+		// local terminal, so only output is played back
+		if kind != session.CastOutput {
+			continue
+		}
+
+		at := time.Duration(elapsed*float64(time.Second)) - skipped
+		time.Sleep(time.Until(start.Add(time.Duration(float64(at) / speed))))
+		if _, err := os.Stdout.WriteString(data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gSSH/pb"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// listSessions prints every session known to the server
+func listSessions(client pb.TerminalServiceClient) {
+	res, err := client.ListSessions(context.Background(), &pb.ListSessionsRequest{})
+	if err != nil {
+		fatalf("failed to list sessions: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tOWNER\tSTATUS\tCREATED\tLAST ACTIVITY\tPID\tCOMMAND\tCLIENTS\tSIZE")
+	for _, info := range res.Sessions {
+		size := "-"
+		if info.Size != nil {
+			size = fmt.Sprintf("%dx%d", info.Size.Cols, info.Size.Rows)
+		}
+
+		clients := make([]string, 0, len(info.Attachments))
+		for _, attachment := range info.Attachments {
+			client := fmt.Sprintf("%s (%s)", attachment.ClientAddress, strings.ToLower(attachment.Mode.String()))
+			if attachment.Identity != "" {
+				client = attachment.Identity + "@" + client
+			}
+			clients = append(clients, client)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%v\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			info.Id,
+			orDash(info.Owner),
+			info.SessionStatus,
+			info.CreatedAt.AsTime().Local().Format(time.DateTime),
+			info.LastActivity.AsTime().Local().Format(time.DateTime),
+			info.ShellPid,
+			orDash(info.ForegroundCommand),
+			orDash(strings.Join(clients, ", ")),
+			size,
+		)
+	}
+	w.Flush()
+}
+
+// terminateSession kills the session given by --id on the server
+func terminateSession(client pb.TerminalServiceClient, sessionID string) {
+	if sessionID == "" {
+		fatalf("terminate requires the --id of the session")
+	}
+
+	res, err := client.TerminateSession(context.Background(), &pb.SessionRequest{Id: &sessionID})
+	if err != nil {
+		fatalf("failed to terminate session: %v", err)
+	}
+
+	// Sessions that were already gone have no exit status
+	if res.ExitStatus == nil {
+		fmt.Printf("Session %s: %v\n", res.Id, res.SessionStatus)
+		return
+	}
+	fmt.Printf("Session %s: %v (exit code %d)\n", res.Id, res.SessionStatus, res.ExitStatus.GetCode())
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// drainServer switches the drain mode of the server on or off
+func drainServer(client pb.TerminalServiceClient, enabled bool) {
+	res, err := client.Drain(context.Background(), &pb.DrainRequest{Enabled: enabled})
+	if err != nil {
+		fatalf("failed to drain server: %v", err)
+	}
+
+	if res.Draining {
+		fmt.Printf("Server is draining, %d sessions still running\n", res.Sessions)
+	} else {
+		fmt.Printf("Server accepts new sessions, %d sessions running\n", res.Sessions)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"gSSH/pkg/tracing"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tracedCredentials traces the TLS handshakes of the connection as part of
+// connecting. gRPC dials in the background, so the handshake context doesn't
+// carry the span of the RPC waiting for it.
+type tracedCredentials struct {
+	credentials.TransportCredentials
+	ctx context.Context
+}
+
+func (c tracedCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (_ net.Conn, _ credentials.AuthInfo, err error) {
+	_, span := tracing.Start(c.ctx, "tls.handshake")
+	defer func() { tracing.End(span, err) }()
+	return c.TransportCredentials.ClientHandshake(ctx, authority, conn)
+}
+
+func (c tracedCredentials) Clone() credentials.TransportCredentials {
+	return tracedCredentials{TransportCredentials: c.TransportCredentials.Clone(), ctx: c.ctx}
+}
@@ -1,7 +1,10 @@
 package env
 
 import (
+	"errors"
+	"io/fs"
 	"log"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,21 +15,145 @@ type Env struct {
 	ServerAddress  string `mapstructure:"SERVER_ADDRESS"`
 	ServerPort     int    `mapstructure:"SERVER_PORT"`
 	ServerCertPort int    `mapstructure:"SERVER_CERT_PORT"`
+
+	// Session reaper, a zero value disables the limit
+	SessionIdleTimeout time.Duration `mapstructure:"SESSION_IDLE_TIMEOUT"`
+	SessionMaxAge      time.Duration `mapstructure:"SESSION_MAX_AGE"`
+	SessionWarning     time.Duration `mapstructure:"SESSION_WARNING"`
+	MaxSessions        int           `mapstructure:"MAX_SESSIONS"`
+
+	// How long a shutdown waits for sessions to end before terminating them
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+
+	// Bytes of recent output kept per session to replay on reattach
+	SessionScrollback int `mapstructure:"SESSION_SCROLLBACK"`
+
+	// Server certificate and the CA that issues it and client certificates.
+	// The certificate is renewed this long before it expires when the CA key
+	// is available.
+	TLSCert        string        `mapstructure:"TLS_CERT"`
+	TLSKey         string        `mapstructure:"TLS_KEY"`
+	TLSCACert      string        `mapstructure:"TLS_CA_CERT"`
+	TLSCAKey       string        `mapstructure:"TLS_CA_KEY"`
+	TLSRenewBefore time.Duration `mapstructure:"TLS_RENEW_BEFORE"`
+
+	// HTTPS endpoint on SERVER_CERT_PORT serving the trust bundle to new
+	// clients, signed with the pre-shared key when one is set
+	BootstrapEnabled bool   `mapstructure:"BOOTSTRAP_ENABLED"`
+	BootstrapPSK     string `mapstructure:"BOOTSTRAP_PSK"`
+
+	// Mutual TLS, client certificates are only verified when a CA bundle is set
+	TLSClientCA       string `mapstructure:"TLS_CLIENT_CA"`
+	TLSClientAuth     string `mapstructure:"TLS_CLIENT_AUTH"`     // "require" or "optional"
+	TLSClientIdentity string `mapstructure:"TLS_CLIENT_IDENTITY"` // "subject" or "san"
+
+	// SSH public key authentication, enabled when an authorized_keys path is set
+	AuthorizedKeysFile string        `mapstructure:"AUTHORIZED_KEYS_FILE"`
+	AuthTokenTTL       time.Duration `mapstructure:"AUTH_TOKEN_TTL"`
+
+	// Hashed API tokens with their scopes, reloaded when the file changes
+	APITokensFile string `mapstructure:"API_TOKENS_FILE"`
+
+	// Users who may attach to, signal and terminate every session
+	AdminUsers []string `mapstructure:"ADMIN_USERS"`
+
+	// Run shells and commands as the local account of each user, which
+	// needs the server to run as root. USER_MAP maps gSSH users to
+	// differently named accounts, as "<gssh user>=<local user>" pairs.
+	RunAsUser bool     `mapstructure:"RUN_AS_USER"`
+	UserMap   []string `mapstructure:"USER_MAP"`
+
+	// Whether users may get a root shell that way, through their own name or
+	// the user map, like sshd's PermitRootLogin
+	PermitRootLogin bool `mapstructure:"PERMIT_ROOT_LOGIN"`
+
+	// Interactive sessions are recorded as asciicast files below this
+	// directory, one subdirectory per user, when it is set
+	RecordingsDir string `mapstructure:"RECORDINGS_DIR"`
+
+	// Audit log of session lifecycle, commands and logins as JSON lines, in a
+	// file rotated past AUDIT_LOG_MAX_SIZE megabytes and/or sent to syslog
+	AuditLogFile       string `mapstructure:"AUDIT_LOG_FILE"`
+	AuditLogMaxSize    int    `mapstructure:"AUDIT_LOG_MAX_SIZE"`
+	AuditLogMaxBackups int    `mapstructure:"AUDIT_LOG_MAX_BACKUPS"`
+	AuditSyslog        bool   `mapstructure:"AUDIT_SYSLOG"`
+	AuditSyslogSocket  string `mapstructure:"AUDIT_SYSLOG_SOCKET"`
+
+	// Prometheus metrics on https://<SERVER_ADDRESS>:<SERVER_CERT_PORT>/metrics
+	MetricsEnabled bool `mapstructure:"METRICS_ENABLED"`
+
+	// The health service reports NOT_SERVING once the server certificate
+	// expires within this long
+	HealthCertMinValidity time.Duration `mapstructure:"HEALTH_CERT_MIN_VALIDITY"`
+
+	// OpenTelemetry traces are exported to this OTLP gRPC collector, e.g.
+	// localhost:4317, when it is set
+	TracingEndpoint    string  `mapstructure:"TRACING_ENDPOINT"`
+	TracingInsecure    bool    `mapstructure:"TRACING_INSECURE"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+
+	// Server log output, "text" or "json", and the minimum level logged
+	LogFormat string `mapstructure:"LOG_FORMAT"`
+	LogLevel  string `mapstructure:"LOG_LEVEL"`
 }
 
 func NewEnv() *Env {
 	env := Env{}
 	viper.SetConfigFile(".env")
 
+	viper.SetDefault("SESSION_IDLE_TIMEOUT", 0)
+	viper.SetDefault("SESSION_MAX_AGE", 0)
+	viper.SetDefault("SESSION_WARNING", time.Minute)
+	viper.SetDefault("MAX_SESSIONS", 0)
+	viper.SetDefault("SESSION_SCROLLBACK", 64*1024)
+	viper.SetDefault("SHUTDOWN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("TLS_CERT", "cert/server.crt")
+	viper.SetDefault("TLS_KEY", "cert/server.key")
+	viper.SetDefault("TLS_CA_CERT", "cert/ca.crt")
+	viper.SetDefault("TLS_CA_KEY", "cert/ca.key")
+	viper.SetDefault("TLS_RENEW_BEFORE", 30*24*time.Hour)
+	viper.SetDefault("BOOTSTRAP_ENABLED", true)
+	viper.SetDefault("BOOTSTRAP_PSK", "")
+	viper.SetDefault("TLS_CLIENT_CA", "")
+	viper.SetDefault("TLS_CLIENT_AUTH", "require")
+	viper.SetDefault("TLS_CLIENT_IDENTITY", "subject")
+	viper.SetDefault("AUTHORIZED_KEYS_FILE", "")
+	viper.SetDefault("AUTH_TOKEN_TTL", 12*time.Hour)
+	viper.SetDefault("API_TOKENS_FILE", "")
+	viper.SetDefault("ADMIN_USERS", []string{})
+	viper.SetDefault("RUN_AS_USER", false)
+	viper.SetDefault("USER_MAP", []string{})
+	viper.SetDefault("PERMIT_ROOT_LOGIN", false)
+	viper.SetDefault("RECORDINGS_DIR", "")
+	viper.SetDefault("AUDIT_LOG_FILE", "")
+	viper.SetDefault("AUDIT_LOG_MAX_SIZE", 100)
+	viper.SetDefault("AUDIT_LOG_MAX_BACKUPS", 10)
+	viper.SetDefault("AUDIT_SYSLOG", false)
+	viper.SetDefault("AUDIT_SYSLOG_SOCKET", "/dev/log")
+	viper.SetDefault("METRICS_ENABLED", false)
+	viper.SetDefault("HEALTH_CERT_MIN_VALIDITY", 24*time.Hour)
+	viper.SetDefault("TRACING_ENDPOINT", "")
+	viper.SetDefault("TRACING_INSECURE", false)
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("LOG_FORMAT", "text")
+	viper.SetDefault("LOG_LEVEL", "info")
+
+	// Every setting has a default, so a missing .env leaves them all at it.
+	// That includes authentication being off, so it is never silent.
 	err := viper.ReadInConfig()
-	if err != nil {
-		log.Fatalf("Couldn't find the file .env: %s", err)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("WARNING: no .env in the working directory, every setting is at its default")
+	} else if err != nil {
+		log.Fatalf("Couldn't read the file .env: %s", err)
 	}
 
 	err = viper.Unmarshal(&env)
 	if err != nil {
 		log.Fatalf("Environment couldn't be loaded: %s", err)
 	}
+	if env.SessionScrollback <= 0 {
+		log.Fatalf("SESSION_SCROLLBACK must be a positive number of bytes, got %d", env.SessionScrollback)
+	}
 
 	return &env
 }
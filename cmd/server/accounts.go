@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gSSH/pkg/auth"
+	"gSSH/pkg/session"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// parseUserMap parses "<gssh user>=<local user>" pairs
+func parseUserMap(pairs []string) (map[string]string, error) {
+	userMap := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, local, ok := strings.Cut(pair, "=")
+		if !ok || name == "" || local == "" {
+			return nil, fmt.Errorf("invalid user mapping %q, expected <gssh user>=<local user>", pair)
+		}
+		userMap[name] = local
+	}
+	return userMap, nil
+}
+
+// account returns the local account the client's shells and commands run as,
+// or nil when they run as the server's own user. Users map to the account of
+// the same name unless the user map says otherwise.
+func (s *Server) account(ctx context.Context) (*session.Account, error) {
+	if !s.runAsUser {
+		return nil, nil
+	}
+
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "an authenticated user is required to run commands")
+	}
+
+	name := identity.Name
+	if local, ok := s.userMap[name]; ok {
+		name = local
+	}
+
+	account, err := session.LookupAccount(name)
+	if err != nil {
+		slog.Warn("no local account", "user", identity.Name, "account", name, "error", err)
+		return nil, status.Errorf(codes.PermissionDenied, "no local account for %s", identity.Name)
+	}
+	// Whatever the name, a certificate, token or mapping may lead to uid 0
+	if account.Uid == 0 && !s.permitRootLogin {
+		slog.Warn("root login refused", "user", identity.Name, "account", account.Name)
+		return nil, status.Errorf(codes.PermissionDenied, "%s may not run commands as root", identity.Name)
+	}
+	return account, nil
+}
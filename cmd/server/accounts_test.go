@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"gSSH/pkg/auth"
+	"gSSH/pkg/session"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccountRefusesRoot(t *testing.T) {
+	if _, err := session.LookupAccount("root"); err != nil {
+		t.Skipf("no root account: %v", err)
+	}
+	if _, err := session.LookupAccount("nobody"); err != nil {
+		t.Skipf("no nobody account: %v", err)
+	}
+
+	userMap, err := parseUserMap([]string{"ci=root", "guest=nobody"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		user            string
+		permitRootLogin bool
+		want            string // Account, empty when refused
+	}{
+		{"root", false, ""},
+		{"ci", false, ""},
+		{"guest", false, "nobody"},
+		{"nobody", false, "nobody"},
+		{"root", true, "root"},
+		{"ci", true, "root"},
+	} {
+		server := &Server{runAsUser: true, userMap: userMap, permitRootLogin: test.permitRootLogin}
+		ctx := auth.NewContext(context.Background(), &auth.Identity{Name: test.user, Method: "mtls"})
+
+		account, err := server.account(ctx)
+		if test.want == "" {
+			if status.Code(err) != codes.PermissionDenied {
+				t.Errorf("%s with PERMIT_ROOT_LOGIN=%v: got %v, want PermissionDenied", test.user, test.permitRootLogin, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s with PERMIT_ROOT_LOGIN=%v: %v", test.user, test.permitRootLogin, err)
+			continue
+		}
+		if account.Name != test.want {
+			t.Errorf("%s with PERMIT_ROOT_LOGIN=%v runs as %s, want %s", test.user, test.permitRootLogin, account.Name, test.want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"gSSH/pb"
+	"gSSH/pkg/audit"
+	"gSSH/pkg/auth"
+	"gSSH/pkg/session"
+	"log/slog"
+	"net"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// methodScopes lists the API token scopes allowed to call each RPC. Whether
+// a client attaches read-only is only known from the request, so the
+// handlers check the shell scope of writers themselves.
+var methodScopes = map[string][]auth.Scope{
+	pb.TerminalService_Authenticate_FullMethodName:         {auth.ScopeExec, auth.ScopeObserve, auth.ScopeShell},
+	pb.TerminalService_Exec_FullMethodName:                 {auth.ScopeExec},
+	pb.TerminalService_RequestSession_FullMethodName:       {auth.ScopeObserve, auth.ScopeShell},
+	pb.TerminalService_ExecuteCommand_FullMethodName:       {auth.ScopeObserve, auth.ScopeShell},
+	pb.TerminalService_ListSessions_FullMethodName:         {auth.ScopeObserve, auth.ScopeShell},
+	pb.TerminalService_SignalSession_FullMethodName:        {auth.ScopeShell},
+	pb.TerminalService_TerminateSession_FullMethodName:     {auth.ScopeShell},
+	pb.TerminalService_MakeSessionAvailable_FullMethodName: {auth.ScopeShell},
+
+	// Health checks are public, and any client may look the API up
+	healthpb.Health_Check_FullMethodName:                                   {auth.ScopeExec, auth.ScopeObserve, auth.ScopeShell},
+	healthpb.Health_Watch_FullMethodName:                                   {auth.ScopeExec, auth.ScopeObserve, auth.ScopeShell},
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:      {auth.ScopeExec, auth.ScopeObserve, auth.ScopeShell},
+	reflectionalphapb.ServerReflection_ServerReflectionInfo_FullMethodName: {auth.ScopeExec, auth.ScopeObserve, auth.ScopeShell},
+}
+
+// Authenticate runs the public key challenge: the client names its user and
+// key, signs the nonce it gets back, and receives a session token once the
+// signature checks out against the user's authorized_keys
+func (s *Server) Authenticate(stream pb.TerminalService_AuthenticateServer) error {
+	if s.publicKeys == nil {
+		return status.Error(codes.FailedPrecondition, "public key authentication is disabled")
+	}
+
+	req, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to receive initial request: %v", err)
+	}
+	start := req.GetStart()
+	if start == nil {
+		return status.Error(codes.InvalidArgument, "first authentication message must name the user and key")
+	}
+
+	key, err := ssh.ParsePublicKey(start.PublicKey)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+	}
+
+	var addr net.Addr
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		addr = p.Addr
+	}
+
+	// The reason stays in the server logs, clients only learn the key was refused
+	entry, err := s.publicKeys.Authorize(start.User, key, addr)
+	if err != nil {
+		slog.Warn("public key refused", "user", start.User, "client", addr, "error", err)
+		s.audit.Event(claimed(stream.Context(), start.User), audit.AuthFailure, "method", "publickey", "key", ssh.FingerprintSHA256(key), "error", err.Error())
+		return status.Error(codes.Unauthenticated, "public key not accepted")
+	}
+
+	binding, err := auth.ChannelBinding(stream.Context())
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "failed to bind the challenge to the connection: %v", err)
+	}
+	nonce, err := auth.NewNonce()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create challenge: %v", err)
+	}
+	if err := stream.Send(&pb.AuthResponse{Payload: &pb.AuthResponse_Nonce{Nonce: nonce}}); err != nil {
+		return err
+	}
+
+	req, err = stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to receive signature: %v", err)
+	}
+
+	signature := new(ssh.Signature)
+	if err := ssh.Unmarshal(req.GetSignature(), signature); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid signature: %v", err)
+	}
+
+	identity, err := s.publicKeys.Verify(entry, start.User, nonce, binding, signature)
+	if err != nil {
+		slog.Warn("public key authentication failed", "user", start.User, "client", addr, "error", err)
+		s.audit.Event(claimed(stream.Context(), start.User), audit.AuthFailure, "method", "publickey", "key", ssh.FingerprintSHA256(key), "error", err.Error())
+		return status.Error(codes.Unauthenticated, "public key authentication failed")
+	}
+
+	token, expiresAt, err := s.sessionTokens.Issue(identity)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to issue session token: %v", err)
+	}
+	slog.Info("authenticated", "user", start.User, "client", addr, "key", ssh.FingerprintSHA256(key))
+	// The token's use on this connection is part of the same login
+	firstLogin(stream.Context(), identity)
+	s.audit.Event(auth.NewContext(stream.Context(), identity), audit.AuthSuccess, "method", "publickey", "key", ssh.FingerprintSHA256(key))
+
+	return stream.Send(&pb.AuthResponse{Payload: &pb.AuthResponse_Token{Token: &pb.AuthToken{
+		Token:     token,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}}})
+}
+
+// claimed attaches the user a client claims to be to ctx, so failed logins
+// are audited under the name they tried
+func claimed(ctx context.Context, user string) context.Context {
+	return auth.NewContext(ctx, &auth.Identity{Name: user})
+}
+
+// auditAuthenticator records every request authenticator rejects in the
+// audit log, and a login the first time it identifies a user on a connection
+func auditAuthenticator(logger *audit.Logger, authenticator auth.Authenticator) auth.Authenticator {
+	return func(ctx context.Context) (*auth.Identity, error) {
+		identity, err := authenticator(ctx)
+		method, _ := grpc.Method(ctx)
+		switch {
+		case err != nil:
+			logger.Event(ctx, audit.AuthFailure, "rpc", method, "error", err.Error())
+		case identity != nil && firstLogin(ctx, identity):
+			logger.Event(auth.NewContext(ctx, identity), audit.AuthSuccess, "method", identity.Method, "rpc", method)
+		}
+		return identity, err
+	}
+}
+
+// connLogins remembers who already logged in on a connection, so a client
+// making many RPCs is one login in the audit log, not one per RPC
+type connLogins struct {
+	mux   sync.Mutex
+	users map[string]bool
+}
+
+type connLoginsKey struct{}
+
+// firstLogin reports whether identity is new on the connection of ctx, and
+// remembers it
+func firstLogin(ctx context.Context, identity *auth.Identity) bool {
+	logins, ok := ctx.Value(connLoginsKey{}).(*connLogins)
+	if !ok {
+		return true
+	}
+
+	logins.mux.Lock()
+	defer logins.mux.Unlock()
+	key := identity.Method + "\x00" + identity.Name
+	if logins.users[key] {
+		return false
+	}
+	logins.users[key] = true
+	return true
+}
+
+// loginTracker is a stats handler giving each connection its own logins,
+// which the contexts of its RPCs inherit
+type loginTracker struct{}
+
+func (loginTracker) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connLoginsKey{}, &connLogins{users: make(map[string]bool)})
+}
+
+func (loginTracker) HandleConn(context.Context, stats.ConnStats) {}
+
+func (loginTracker) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (loginTracker) HandleRPC(context.Context, stats.RPCStats) {}
+
+// checkPTYAllowed enforces the no-pty and command= restrictions of the
+// authorized key the client logged in with. A nil session stands for a new one.
+func checkPTYAllowed(ctx context.Context, bashSession *session.BashSession) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if identity.NoPTY {
+		return status.Error(codes.PermissionDenied, "interactive sessions are not allowed for this key")
+	}
+	if bashSession != nil && identity.ForcedCommand != "" && bashSession.Command != identity.ForcedCommand {
+		return status.Error(codes.PermissionDenied, "this key is restricted to its forced command")
+	}
+	return nil
+}
+
+// sessionOptions starts the forced command of the client's key, if any,
+// instead of an interactive shell, as the client's local account
+func (s *Server) sessionOptions(ctx context.Context) (session.Options, error) {
+	var options session.Options
+	if identity, ok := auth.FromContext(ctx); ok {
+		options.Command = identity.ForcedCommand
+	}
+
+	account, err := s.account(ctx)
+	if err != nil {
+		return options, err
+	}
+	options.Account = account
+	return options, nil
+}
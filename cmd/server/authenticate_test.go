@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"gSSH/pkg/audit"
+	"gSSH/pkg/auth"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditAuthenticatorLogsOneLoginPerConnection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := audit.New(audit.Options{File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity := &auth.Identity{Name: "alice", Method: "mtls"}
+	accept := auditAuthenticator(logger, func(context.Context) (*auth.Identity, error) { return identity, nil })
+	reject := auditAuthenticator(logger, func(context.Context) (*auth.Identity, error) { return nil, errors.New("bad token") })
+
+	first := loginTracker{}.TagConn(context.Background(), nil)
+	second := loginTracker{}.TagConn(context.Background(), nil)
+	for _, ctx := range []context.Context{first, first, first, second, second} {
+		if _, err := accept(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 3 {
+		_, _ = reject(first)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), audit.AuthSuccess); got != 2 {
+		t.Errorf("%d logins for 5 RPCs on 2 connections, want 2:\n%s", got, data)
+	}
+	if got := strings.Count(string(data), audit.AuthFailure); got != 3 {
+		t.Errorf("%d failures for 3 rejected RPCs, want 3:\n%s", got, data)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"gSSH/pkg/pki"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// newHTTPServer serves the HTTPS endpoints on SERVER_CERT_PORT, the trust
+// bundle and the metrics, with the server certificate. Client certificates
+// are verified against clientCAs when clients present one.
+func newHTTPServer(address string, renewer *pki.Renewer, clientCAs *x509.CertPool, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:    address,
+		Handler: handler,
+		TLSConfig: &tls.Config{
+			GetCertificate: renewer.GetCertificate,
+			ClientCAs:      clientCAs,
+			ClientAuth:     tls.VerifyClientCertIfGiven,
+		},
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+}
+
+// serveTrustBundle serves the trust bundle clients fetch before their first
+// connection. Clients can't verify the server certificate yet, so the bundle
+// is signed with the pre-shared key when there is one, and clients also check
+// its fingerprint.
+func serveTrustBundle(w http.ResponseWriter, r *http.Request) {
+	bundle, err := trustBundle()
+	if err != nil {
+		slog.Error("failed to build trust bundle", "error", err)
+		http.Error(w, "trust bundle unavailable", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(bundle)
+}
+
+// trustBundle signs the certificates clients should pin: the CA when there
+// is one, so renewed server certificates stay trusted, or else the server
+// certificate itself. It is read on every request to follow renewals.
+func trustBundle() (*pki.SignedBundle, error) {
+	trusted := environment.TLSCert
+	if _, err := os.Stat(environment.TLSCACert); err == nil {
+		trusted = environment.TLSCACert
+	}
+
+	certificates, err := os.ReadFile(trusted)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := pki.NewTrustBundle(certificates)
+	if err != nil {
+		return nil, err
+	}
+	return bundle.Sign([]byte(environment.BootstrapPSK))
+}
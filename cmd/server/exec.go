@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"gSSH/pb"
+	"gSSH/pkg/audit"
+	"gSSH/pkg/auth"
+	"gSSH/pkg/session"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// How long Exec waits for the output of a command that exited, when
+// something it started in the background still holds its stdout or stderr
+const execWaitDelay = 2 * time.Second
+
+// Exec runs a single command without a PTY. The first message of the stream
+// describes the command, the following ones carry its stdin until the client
+// closes its side. Output is streamed back as tagged stdout/stderr chunks and
+// the stream ends with the command's exit status.
+func (s *Server) Exec(stream pb.TerminalService_ExecServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to receive initial request: %v", err)
+	}
+
+	command := req.GetCommand()
+	if command == nil {
+		return status.Error(codes.InvalidArgument, "first exec message must describe the command")
+	}
+	if s.draining.Load() {
+		return status.Error(codes.Unavailable, "server is draining, no new commands are accepted")
+	}
+
+	account, err := s.account(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	// Shell commands run through the login shell of the local account, if any
+	shell := "bash"
+	env := os.Environ()
+	if account != nil {
+		shell = account.Shell
+		env = account.Environ()
+	}
+
+	var cmd *exec.Cmd
+	switch {
+	case len(command.Argv) > 0:
+		cmd = exec.CommandContext(stream.Context(), command.Argv[0], command.Argv[1:]...)
+	case command.Shell != "":
+		cmd = exec.CommandContext(stream.Context(), shell, "-c", command.Shell)
+	default:
+		return status.Error(codes.InvalidArgument, "either argv or shell must be set")
+	}
+
+	// Keys with a forced command only ever run that command, like sshd it
+	// gets what the client asked for in SSH_ORIGINAL_COMMAND
+	if identity, ok := auth.FromContext(stream.Context()); ok && identity.ForcedCommand != "" {
+		original := command.Shell
+		if len(command.Argv) > 0 {
+			original = strings.Join(command.Argv, " ")
+		}
+		cmd = exec.CommandContext(stream.Context(), shell, "-c", identity.ForcedCommand)
+		env = append(env, "SSH_ORIGINAL_COMMAND="+original)
+	}
+
+	if account != nil {
+		account.Apply(cmd)
+	}
+	cmd.Env = env
+
+	// The command leads its own process group, so a cancelled RPC kills what
+	// it started as well, like the teardown of a session does
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.Cancel = func() error {
+		return unix.Kill(-cmd.Process.Pid, unix.SIGKILL)
+	}
+	cmd.WaitDelay = execWaitDelay
+
+	// Both output writers share the stream, which doesn't allow concurrent sends
+	var sendMux sync.Mutex
+	cmd.Stdout = &outputWriter{stream: stream, kind: pb.OutputStream_STDOUT, sendMux: &sendMux}
+	cmd.Stderr = &outputWriter{stream: stream, kind: pb.OutputStream_STDERR, sendMux: &sendMux}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create stdin pipe: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to start command: %v", err)
+	}
+	slog.Info("exec command started", "user", auth.Name(stream.Context()), "pid", cmd.Process.Pid)
+	s.audit.Event(stream.Context(), audit.Exec, "command", strings.Join(cmd.Args, " "), "pid", cmd.Process.Pid)
+
+	// Copy stdin from the client until it closes its side of the stream
+	go func() {
+		defer stdin.Close()
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if _, err := stdin.Write(req.GetStdin()); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case errors.As(err, &exitErr):
+		case errors.Is(err, exec.ErrWaitDelay):
+			slog.Warn("exec command exited with its output still held open", "pid", cmd.Process.Pid)
+		default:
+			return status.Errorf(codes.Internal, "failed to run command: %v", err)
+		}
+	}
+
+	code, signal := session.ProcessExitStatus(cmd.ProcessState)
+	slog.Info("exec command exited", "pid", cmd.Process.Pid, "code", code, "signal", signal)
+
+	sendMux.Lock()
+	defer sendMux.Unlock()
+	return stream.Send(&pb.ExecResponse{Payload: &pb.ExecResponse_Exit{Exit: newExitStatus(code, signal)}})
+}
+
+// outputWriter forwards everything written to it as output chunks of one kind
+type outputWriter struct {
+	stream  pb.TerminalService_ExecServer
+	kind    pb.OutputStream
+	sendMux *sync.Mutex
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.sendMux.Lock()
+	defer w.sendMux.Unlock()
+
+	err := w.stream.Send(&pb.ExecResponse{Payload: &pb.ExecResponse_Output{
+		Output: &pb.OutputChunk{Stream: w.kind, Data: p},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"gSSH/pb"
+	"gSSH/pkg/pki"
+	"gSSH/pkg/session"
+	"log/slog"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// How often the readiness probes run
+const healthCheckInterval = 30 * time.Second
+
+// monitorHealth runs the readiness probes until the server shuts down: the
+// server certificate must stay valid for minValidity and a test PTY must spawn
+func (s *Server) monitorHealth(renewer *pki.Renewer, minValidity time.Duration) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.healthMux.Lock()
+		s.unhealthy = probeHealth(renewer, minValidity)
+		s.healthMux.Unlock()
+		s.updateHealth()
+
+		<-ticker.C
+	}
+}
+
+// probeHealth returns why the server can't serve, or "" when it can
+func probeHealth(renewer *pki.Renewer, minValidity time.Duration) string {
+	leaf, err := renewer.Leaf()
+	if err != nil {
+		return fmt.Sprintf("invalid server certificate: %v", err)
+	}
+	if time.Until(leaf.NotAfter) < minValidity {
+		return fmt.Sprintf("server certificate expires at %s", leaf.NotAfter.Format(time.DateTime))
+	}
+	if err := session.CheckPTY(); err != nil {
+		return fmt.Sprintf("failed to spawn a test PTY: %v", err)
+	}
+	return ""
+}
+
+// updateHealth publishes the serving status for the whole server and for
+// TerminalService. Load balancers should route around a node that is
+// draining or failed its probes.
+func (s *Server) updateHealth() {
+	s.healthMux.Lock()
+	defer s.healthMux.Unlock()
+
+	reason := s.unhealthy
+	if s.draining.Load() {
+		reason = "draining"
+	}
+
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if reason != "" {
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	if servingStatus != s.servingStatus {
+		if reason != "" {
+			slog.Warn("health status changed", "status", servingStatus, "reason", reason)
+		} else {
+			slog.Info("health status changed", "status", servingStatus)
+		}
+		s.servingStatus = servingStatus
+	}
+
+	s.health.SetServingStatus("", servingStatus)
+	s.health.SetServingStatus(pb.TerminalService_ServiceDesc.ServiceName, servingStatus)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gSSH/pkg/pki"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// runInit sets up the certificates of a new server: a local CA, unless one
+// exists already, and a server certificate issued by it for hosts
+func runInit(hosts []string, force bool) error {
+	if _, err := os.Stat(environment.TLSCert); err == nil && !force {
+		return fmt.Errorf("%s already exists, pass --force to replace it", environment.TLSCert)
+	}
+
+	var ca *pki.CA
+	_, err := os.Stat(environment.TLSCACert)
+	if errors.Is(err, os.ErrNotExist) {
+		ca, err = pki.NewCA("gSSH CA")
+		if err != nil {
+			return err
+		}
+		if err := ca.Save(environment.TLSCACert, environment.TLSCAKey); err != nil {
+			return fmt.Errorf("failed to save CA: %v", err)
+		}
+		fmt.Printf("Created CA %s\n", environment.TLSCACert)
+	} else {
+		ca, err = pki.LoadCA(environment.TLSCACert, environment.TLSCAKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(hosts) == 0 {
+		hosts = pki.DefaultHosts()
+		if !slices.Contains(hosts, environment.ServerAddress) {
+			hosts = append(hosts, environment.ServerAddress)
+		}
+	}
+
+	cert, err := ca.IssueServer(hosts)
+	if err != nil {
+		return err
+	}
+	if err := pki.SaveCertificate(cert, environment.TLSCert, environment.TLSKey); err != nil {
+		return fmt.Errorf("failed to save server certificate: %v", err)
+	}
+	fmt.Printf("Created server certificate %s for %v\n", environment.TLSCert, hosts)
+	return nil
+}
+
+// runIssue issues a client certificate for mutual TLS to the user name
+func runIssue(name, outDir string) error {
+	if name == "" {
+		return fmt.Errorf("usage: server issue <name> [--out=<dir>]")
+	}
+
+	ca, err := pki.LoadCA(environment.TLSCACert, environment.TLSCAKey)
+	if err != nil {
+		return err
+	}
+
+	cert, err := ca.IssueClient(name)
+	if err != nil {
+		return err
+	}
+
+	certFile := filepath.Join(outDir, name+".crt")
+	keyFile := filepath.Join(outDir, name+".key")
+	if err := pki.SaveCertificate(cert, certFile, keyFile); err != nil {
+		return fmt.Errorf("failed to save client certificate: %v", err)
+	}
+	fmt.Printf("Issued %s and %s, valid until %s\n", certFile, keyFile, cert.Leaf.NotAfter.Format("2006-01-02"))
+	return nil
+}
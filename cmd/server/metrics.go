@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"gSSH/pb"
+	"gSSH/pkg/session"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metrics are the Prometheus metrics of the server, served on /metrics
+type metrics struct {
+	registry *prometheus.Registry
+
+	attaches        *prometheus.CounterVec
+	detaches        *prometheus.CounterVec
+	spawnFailures   prometheus.Counter
+	sessionDuration prometheus.Histogram
+	rpcDuration     *prometheus.HistogramVec
+}
+
+func newMetrics(server *Server) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		attaches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gssh_session_attaches_total",
+			Help: "Clients attached to a session, by attach mode.",
+		}, []string{"mode"}),
+		detaches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gssh_session_detaches_total",
+			Help: "Clients detached from a session, by attach mode.",
+		}, []string{"mode"}),
+		spawnFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gssh_session_spawn_failures_total",
+			Help: "Session shells that failed to start on a PTY.",
+		}),
+		sessionDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "gssh_session_duration_seconds",
+			Help:    "Time from the start of a session's shell to its exit.",
+			Buckets: []float64{10, 60, 300, 900, 3600, 4 * 3600, 12 * 3600, 24 * 3600, 7 * 24 * 3600},
+		}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gssh_rpc_duration_seconds",
+			Help:    "Time to handle an RPC, the whole stream for streaming RPCs, by method and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+	}
+
+	m.registry.MustRegister(
+		m.attaches,
+		m.detaches,
+		m.spawnFailures,
+		m.sessionDuration,
+		m.rpcDuration,
+		&sessionCollector{server: server},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// handler serves the metrics in the Prometheus text format, only to clients
+// with a certificate from TLS_CLIENT_CA like the gRPC port
+func (m *metrics) handler() http.Handler {
+	handler := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// unaryInterceptor measures how long unary RPCs take
+func (m *metrics) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	m.observeRPC(info.FullMethod, start, err)
+	return res, err
+}
+
+// streamInterceptor measures how long streams stay open
+func (m *metrics) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	m.observeRPC(info.FullMethod, start, err)
+	return err
+}
+
+func (m *metrics) observeRPC(fullMethod string, start time.Time, err error) {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	m.rpcDuration.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
+
+// attached counts a client attaching to a session and returns the function
+// counting its detach
+func (m *metrics) attached(mode pb.AttachMode) func() {
+	label := strings.ToLower(mode.String())
+	m.attaches.WithLabelValues(label).Inc()
+	return func() {
+		m.detaches.WithLabelValues(label).Inc()
+	}
+}
+
+// sessionExited records how long the shell of a session ran
+func (m *metrics) sessionExited(bashSession *session.BashSession) {
+	m.sessionDuration.Observe(time.Since(bashSession.CreatedAt).Seconds())
+}
+
+var (
+	sessionsDesc = prometheus.NewDesc(
+		"gssh_sessions",
+		"Sessions on the server, by status.",
+		[]string{"status"}, nil,
+	)
+	sessionInputDesc = prometheus.NewDesc(
+		"gssh_session_input_bytes_total",
+		"Bytes written to the PTYs of all sessions.",
+		nil, nil,
+	)
+	sessionOutputDesc = prometheus.NewDesc(
+		"gssh_session_output_bytes_total",
+		"Bytes read from the PTYs of all sessions.",
+		nil, nil,
+	)
+	drainingDesc = prometheus.NewDesc(
+		"gssh_draining",
+		"Whether the server refuses new sessions.",
+		nil, nil,
+	)
+)
+
+// sessionCollector reports the sessions as they are at scrape time
+type sessionCollector struct {
+	server *Server
+}
+
+func (c *sessionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sessionsDesc
+	ch <- sessionInputDesc
+	ch <- sessionOutputDesc
+	ch <- drainingDesc
+}
+
+func (c *sessionCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := map[pb.SessionStatus]int{
+		pb.SessionStatus_AVAILABLE: 0,
+		pb.SessionStatus_IN_USE:    0,
+	}
+	for _, bashSession := range c.server.sessionList() {
+		counts[sessionStatus(bashSession)]++
+	}
+	for sessionStatus, count := range counts {
+		ch <- prometheus.MustNewConstMetric(sessionsDesc, prometheus.GaugeValue, float64(count), strings.ToLower(sessionStatus.String()))
+	}
+
+	in, out := session.Traffic()
+	ch <- prometheus.MustNewConstMetric(sessionInputDesc, prometheus.CounterValue, float64(in))
+	ch <- prometheus.MustNewConstMetric(sessionOutputDesc, prometheus.CounterValue, float64(out))
+
+	var draining float64
+	if c.server.draining.Load() {
+		draining = 1
+	}
+	ch <- prometheus.MustNewConstMetric(drainingDesc, prometheus.GaugeValue, draining)
+}
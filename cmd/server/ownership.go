@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"gSSH/pb"
+	"gSSH/pkg/auth"
+	"gSSH/pkg/session"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authorizeSession checks the client may use the session the way mode says.
+// Owners and admins may do anything, the viewers the owner named may only
+// observe. Signalling, terminating and replacing the shell count as writing.
+// Ownership follows the identity, not its display name, so anonymous clients
+// only share the sessions nobody owns, and only while authentication is off.
+func (s *Server) authorizeSession(ctx context.Context, bashSession *session.BashSession, mode pb.AttachMode) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		if !s.authRequired && bashSession.Owner == "" {
+			return nil
+		}
+		return status.Errorf(codes.PermissionDenied, "session %s belongs to another user", bashSession.Id)
+	}
+
+	if (bashSession.Owner != "" && bashSession.Owner == identity.Name) || auth.IsAdmin(ctx) {
+		return nil
+	}
+	if mode == pb.AttachMode_OBSERVER && slices.Contains(bashSession.Viewers, identity.Name) {
+		return nil
+	}
+
+	if mode == pb.AttachMode_OBSERVER {
+		return status.Errorf(codes.PermissionDenied, "%s may not observe session %s", identity.Name, bashSession.Id)
+	}
+	return status.Errorf(codes.PermissionDenied, "session %s belongs to another user", bashSession.Id)
+}
+
+// owner is who a session created by the request belongs to, nobody when the
+// client is anonymous
+func owner(ctx context.Context) string {
+	if identity, ok := auth.FromContext(ctx); ok {
+		return identity.Name
+	}
+	return ""
+}
+
+// ownerName shows the owner of a session, the way auth.Name shows a client
+func ownerName(bashSession *session.BashSession) string {
+	if bashSession.Owner == "" {
+		return "anonymous"
+	}
+	return bashSession.Owner
+}
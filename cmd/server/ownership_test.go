@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"gSSH/pb"
+	"gSSH/pkg/auth"
+	"gSSH/pkg/session"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeSession(t *testing.T) {
+	owned := &session.BashSession{Id: "owned", Owner: "alice", Viewers: []string{"bob"}}
+	anonymous := &session.BashSession{Id: "anonymous"}
+
+	alice := &auth.Identity{Name: "alice", Method: "mtls"}
+	bob := &auth.Identity{Name: "bob", Method: "mtls"}
+	mallory := &auth.Identity{Name: "mallory", Method: "mtls"}
+	admin := &auth.Identity{Name: "ci", Method: "token", Scopes: []auth.Scope{auth.ScopeAdmin}}
+	// A real account that happens to be called like the placeholder
+	named := &auth.Identity{Name: "anonymous", Method: "mtls"}
+
+	for _, test := range []struct {
+		name         string
+		identity     *auth.Identity // nil for an anonymous client
+		authRequired bool
+		session      *session.BashSession
+		mode         pb.AttachMode
+		allowed      bool
+	}{
+		{"owner writes", alice, true, owned, pb.AttachMode_WRITER, true},
+		{"owner observes", alice, true, owned, pb.AttachMode_OBSERVER, true},
+		{"viewer observes", bob, true, owned, pb.AttachMode_OBSERVER, true},
+		{"viewer writes", bob, true, owned, pb.AttachMode_WRITER, false},
+		{"admin writes", admin, true, owned, pb.AttachMode_WRITER, true},
+		{"admin observes", admin, true, owned, pb.AttachMode_OBSERVER, true},
+		{"stranger writes", mallory, true, owned, pb.AttachMode_WRITER, false},
+		{"stranger observes", mallory, true, owned, pb.AttachMode_OBSERVER, false},
+		{"anonymous client on an owned session", nil, false, owned, pb.AttachMode_OBSERVER, false},
+		{"anonymous client on an anonymous session", nil, false, anonymous, pb.AttachMode_WRITER, true},
+		{"anonymous client while authentication is required", nil, true, anonymous, pb.AttachMode_OBSERVER, false},
+		{"user named anonymous on an anonymous session", named, false, anonymous, pb.AttachMode_WRITER, false},
+		{"user on an anonymous session", alice, false, anonymous, pb.AttachMode_OBSERVER, false},
+		{"admin on an anonymous session", admin, false, anonymous, pb.AttachMode_WRITER, true},
+	} {
+		server := &Server{authRequired: test.authRequired}
+		ctx := context.Background()
+		if test.identity != nil {
+			ctx = auth.NewContext(ctx, test.identity)
+		}
+
+		err := server.authorizeSession(ctx, test.session, test.mode)
+		if test.allowed && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if !test.allowed && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s: got %v, want PermissionDenied", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gSSH/pkg/audit"
+	"gSSH/pkg/session"
+	"log/slog"
+	"time"
+)
+
+// How often the reaper looks for expired sessions
+const reaperInterval = 10 * time.Second
+
+// reap periodically terminates sessions that were idle for longer than the
+// idle timeout or that outlived the maximum session age. Attached clients are
+// warned once a session gets close to its deadline.
+func (s *Server) reap() {
+	if s.idleTimeout <= 0 && s.maxAge <= 0 {
+		return
+	}
+
+	// Deadline each session was last warned about, so every deadline is only
+	// announced once, and again if activity moves it
+	warned := make(map[*session.BashSession]time.Time)
+
+	ticker := time.NewTicker(reaperInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		var expired []*session.BashSession
+		warnings := make(map[*session.BashSession]string)
+
+		s.sessionMux.Lock()
+		for id, bashSession := range s.sessions {
+			deadline, ok := s.sessionDeadline(bashSession)
+			if !ok {
+				continue
+			}
+
+			if !now.Before(deadline) {
+				delete(s.sessions, id)
+				delete(warned, bashSession)
+				expired = append(expired, bashSession)
+				continue
+			}
+
+			if now.Add(s.warning).After(deadline) && !warned[bashSession].Equal(deadline) {
+				warned[bashSession] = deadline
+				warnings[bashSession] = fmt.Sprintf("session will be closed in %s due to inactivity or age limit", deadline.Sub(now).Round(time.Second))
+			}
+		}
+
+		// Forget sessions that ended on their own
+		for bashSession := range warned {
+			if s.sessions[bashSession.Id] != bashSession {
+				delete(warned, bashSession)
+			}
+		}
+		s.sessionMux.Unlock()
+
+		// Sessions are only warned once sessionMux is released
+		for bashSession, warning := range warnings {
+			bashSession.Warn(warning)
+		}
+		for _, bashSession := range expired {
+			slog.Info("reaping expired session", "session", bashSession.Id)
+			s.audit.Event(context.Background(), audit.SessionTerminate, "session", bashSession.Id, "owner", bashSession.Owner, "reason", "expired")
+			go bashSession.Terminate(terminateGracePeriod)
+		}
+	}
+}
+
+// sessionDeadline returns when the session expires, whichever of the idle
+// timeout and the maximum age comes first
+func (s *Server) sessionDeadline(bashSession *session.BashSession) (time.Time, bool) {
+	var deadline time.Time
+	if s.idleTimeout > 0 {
+		deadline = bashSession.LastActivity().Add(s.idleTimeout)
+	}
+	if s.maxAge > 0 {
+		if maxAge := bashSession.CreatedAt.Add(s.maxAge); deadline.IsZero() || maxAge.Before(deadline) {
+			deadline = maxAge
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
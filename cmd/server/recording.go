@@ -0,0 +1,33 @@
+package main
+
+import (
+	"gSSH/pkg/session"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startRecording opens the recording of a new session of user, or returns nil
+// when recording is disabled. Sessions that can't be recorded are refused.
+func (s *Server) startRecording(user, sessionId string) (*session.Recording, error) {
+	if s.recordingsDir == "" {
+		return nil, nil
+	}
+
+	path := session.RecordingPath(s.recordingsDir, user, sessionId, time.Now())
+	// The size of the client's terminal follows as a resize event once it attaches
+	recording, err := session.NewRecording(path, session.CastHeader{
+		Width:  80,
+		Height: 24,
+		Title:  user + " " + sessionId,
+	})
+	if err != nil {
+		slog.Error("failed to start recording", "session", sessionId, "user", user, "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to record session: %v", err)
+	}
+
+	slog.Info("recording session", "session", sessionId, "user", user, "file", path)
+	return recording, nil
+}
@@ -2,52 +2,101 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	env "gSSH/cmd"
 	"gSSH/pb"
+	"gSSH/pkg/audit"
+	"gSSH/pkg/auth"
 	"gSSH/pkg/session"
+	"gSSH/pkg/tracing"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
+	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Server struct {
 	pb.UnimplementedTerminalServiceServer
 	sessions   map[string]*session.BashSession
 	sessionMux sync.Mutex
-}
 
-type BashSession struct {
-	Id              string
-	TerminalCommand *exec.Cmd
-	Ptmx            *os.File
-	InUse           bool
+	// Public key authentication, nil when disabled
+	publicKeys    *auth.PublicKeyAuthenticator
+	sessionTokens *auth.SessionTokens
+
+	// API tokens for automation, nil when disabled
+	apiTokens *auth.APITokens
+
+	// Refuse new sessions while existing ones keep running
+	draining atomic.Bool
+
+	// Run shells as the local account of each user instead of the server's
+	// Whether every RPC but the public ones needs an identity
+	authRequired bool
+
+	runAsUser       bool
+	userMap         map[string]string
+	permitRootLogin bool
+
+	// Directory interactive sessions are recorded to, empty when disabled
+	recordingsDir string
+
+	// Audit log of who did what, nil when disabled
+	audit *audit.Logger
+
+	metrics *metrics
+
+	// Readiness reported by the gRPC health service
+	health        *health.Server
+	healthMux     sync.Mutex
+	unhealthy     string // Why the last probes failed, empty when they passed
+	servingStatus healthpb.HealthCheckResponse_ServingStatus
+
+	// Session limits enforced by the reaper, zero disables them
+	idleTimeout time.Duration
+	maxAge      time.Duration
+	warning     time.Duration
+	maxSessions int
 }
 
-var (
-	crt         = "cert/server.crt"
-	key         = "cert/server.key"
-	environment = env.NewEnv()
-)
+// Time a shell gets to exit after SIGHUP before it is killed
+const terminateGracePeriod = 5 * time.Second
+
+var environment = env.NewEnv()
 
 func init() {
 	viper.SetDefault("port", environment.ServerPort)
 
 	pflag.Int("port", environment.ServerPort, "Port to run the TCP connection")
-	pflag.Parse()
+	pflag.StringSlice("hosts", nil, "Host names and IPs of the server certificate, for init")
+	pflag.Bool("force", false, "Replace the existing server certificate, for init")
+	pflag.String("out", ".", "Directory to write the client certificate to, for issue")
 
 	viper.BindPFlag("port", pflag.Lookup("port"))
 
@@ -64,39 +113,115 @@ func (s *Server) RequestSession(ctx context.Context, req *pb.SessionRequest) (*p
 
 	if req.GetId() != "" {
 		sessionId = req.GetId()
-		fmt.Printf("Requested sessionId: %s\n", sessionId)
+		slog.Debug("session requested", "session", sessionId)
 	} else {
 		sessionId = generateSessionId()
-		fmt.Printf("Generated new sessionId: %s\n", sessionId)
+		slog.Debug("generated session ID", "session", sessionId)
+	}
+
+	if _, err := checkAttachMode(ctx, req.Mode); err != nil {
+		return nil, err
 	}
 
+	// Joining an existing session looks at its attachments, which is done
+	// without holding sessionMux so a busy session can't stall the server
 	s.sessionMux.Lock()
+	session, exists := s.sessions[sessionId]
+	if exists {
+		s.sessionMux.Unlock()
+		return s.joinSession(ctx, session, req)
+	}
 	defer s.sessionMux.Unlock()
 
-	if session, exists := s.sessions[sessionId]; exists {
-		if session.InUse {
-			fmt.Printf("Session %s is in use.\n", sessionId)
-			return &pb.SessionResponse{
-				Id:            sessionId,
-				SessionStatus: pb.SessionStatus_IN_USE,
-			}, nil
-		}
-	} else {
-		newSession, err := session.New(sessionId)
-		if err != nil {
-			return &pb.SessionResponse{}, err
-		}
+	if s.draining.Load() {
+		return nil, status.Error(codes.Unavailable, "server is draining, no new sessions are accepted")
+	}
+	// Starting a shell is never read-only, whatever the mode asked for
+	if err := auth.CheckScope(ctx, auth.ScopeShell); err != nil {
+		return nil, err
+	}
+	if err := checkPTYAllowed(ctx, nil); err != nil {
+		return nil, err
+	}
+	if s.maxSessions > 0 && len(s.sessions) >= s.maxSessions {
+		return nil, status.Errorf(codes.ResourceExhausted, "maximum number of sessions reached: %d", s.maxSessions)
+	}
+
+	options, err := s.sessionOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	options.Recording, err = s.startRecording(auth.Name(ctx), sessionId)
+	if err != nil {
+		return nil, err
+	}
 
-		s.sessions[sessionId] = newSession
-		fmt.Printf("Created new session %s and marked as in use.\n", sessionId)
+	_, span := tracing.Start(ctx, "session.spawn", trace.WithAttributes(attribute.String("session.id", sessionId)))
+	newSession, err := session.New(sessionId, options)
+	tracing.End(span, err)
+	if err != nil {
+		s.metrics.spawnFailures.Inc()
+		return &pb.SessionResponse{}, err
 	}
 
+	newSession.SharedWrite = req.SharedWrite
+	newSession.Owner = owner(ctx)
+	newSession.Viewers = req.Viewers
+	s.sessions[sessionId] = newSession
+	go s.removeOnExit(newSession)
+	slog.Info("session created", "session", sessionId, "user", auth.Name(ctx), "pid", newSession.TerminalCommand.Process.Pid)
+	s.audit.Event(ctx, audit.SessionCreate, "session", sessionId, "command", options.Command)
+
 	return &pb.SessionResponse{
 		Id:            sessionId,
 		SessionStatus: pb.SessionStatus_AVAILABLE,
 	}, nil
 }
 
+// joinSession checks whether the client may attach to an existing session.
+// Observers can always join, writers only while nobody else writes or when
+// the session is shared.
+func (s *Server) joinSession(ctx context.Context, bashSession *session.BashSession, req *pb.SessionRequest) (*pb.SessionResponse, error) {
+	if err := s.authorizeSession(ctx, bashSession, req.Mode); err != nil {
+		return nil, err
+	}
+	if err := checkPTYAllowed(ctx, bashSession); err != nil {
+		return nil, err
+	}
+
+	if req.Mode == pb.AttachMode_WRITER && !bashSession.CanWrite() {
+		slog.Info("session in use", "session", bashSession.Id, "user", auth.Name(ctx))
+		return &pb.SessionResponse{
+			Id:            bashSession.Id,
+			SessionStatus: pb.SessionStatus_IN_USE,
+		}, nil
+	}
+
+	return &pb.SessionResponse{
+		Id:            bashSession.Id,
+		SessionStatus: pb.SessionStatus_AVAILABLE,
+	}, nil
+}
+
+// removeOnExit drops the session from the server once its shell exits, which
+// moves it to TERMINATED for every later request
+func (s *Server) removeOnExit(bashSession *session.BashSession) {
+	<-bashSession.Exited()
+
+	s.metrics.sessionExited(bashSession)
+	code, signal := bashSession.ExitStatus()
+	s.audit.Event(context.Background(), audit.SessionExit, "session", bashSession.Id, "owner", bashSession.Owner, "code", code, "signal", signal)
+
+	s.sessionMux.Lock()
+	defer s.sessionMux.Unlock()
+
+	// The ID may already point to a newer session
+	if s.sessions[bashSession.Id] == bashSession {
+		delete(s.sessions, bashSession.Id)
+		slog.Info("session removed", "session", bashSession.Id)
+	}
+}
+
 func (s *Server) ExecuteCommand(stream pb.TerminalService_ExecuteCommandServer) error {
 	req, err := stream.Recv()
 	if err != nil {
@@ -104,7 +229,6 @@ func (s *Server) ExecuteCommand(stream pb.TerminalService_ExecuteCommandServer)
 	}
 
 	sessionId := req.SessionId
-	fmt.Printf("Executing command for sessionId: %s\n", sessionId)
 
 	s.sessionMux.Lock()
 	bashSession, ok := s.sessions[sessionId]
@@ -112,54 +236,273 @@ func (s *Server) ExecuteCommand(stream pb.TerminalService_ExecuteCommandServer)
 		s.sessionMux.Unlock()
 		return status.Errorf(codes.NotFound, "session not found: %s", sessionId)
 	}
-	bashSession.InUse = true // Mark session as in use
 	s.sessionMux.Unlock()
 
-	fmt.Printf("Marked session %s as in use during ExecuteCommand.\n", sessionId)
+	if err := s.authorizeSession(stream.Context(), bashSession, req.Mode); err != nil {
+		return err
+	}
+	if err := checkPTYAllowed(stream.Context(), bashSession); err != nil {
+		return err
+	}
+	mode, err := checkAttachMode(stream.Context(), req.Mode)
+	if err != nil {
+		return err
+	}
 
-	ptmx := bashSession.Ptmx
-	defer func() { _ = ptmx.Close() }()
+	var clientAddress string
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		clientAddress = p.Addr.String()
+	}
 
-	// Goroutine to send the session output to client
+	identity := auth.Name(stream.Context())
+	attachment, scrollback, err := bashSession.Attach(clientAddress, identity, mode)
+	if errors.Is(err, session.ErrWriterAttached) {
+		return status.Errorf(codes.FailedPrecondition, "session %s is in use: %v", sessionId, err)
+	} else if err != nil {
+		return sessionError(err, "attach to session")
+	}
+
+	// Whatever ends the stream only detaches the client, the shell keeps
+	// running until it exits or the session is terminated
+	detached := s.metrics.attached(req.Mode)
+	defer func() {
+		attachment.Detach()
+		detached()
+		slog.Info("client detached", "session", sessionId, "client", clientAddress, "user", identity)
+		s.audit.Event(stream.Context(), audit.SessionDetach, "session", sessionId)
+	}()
+	slog.Info("client attached", "session", sessionId, "client", clientAddress, "user", identity, "mode", req.Mode)
+	s.audit.Event(stream.Context(), audit.SessionAttach, "session", sessionId, "mode", req.Mode.String())
+
+	// The initial request may already carry a payload, usually the client's window size
+	commands := &audit.LineBuffer{}
+	if err := s.handleCommandRequest(stream.Context(), bashSession, attachment, commands, req); err != nil {
+		return err
+	}
+
+	// Output and notices are sent from their own goroutines, and gRPC streams
+	// don't allow concurrent sends
+	var sendMux sync.Mutex
+	send := func(res *pb.CommandResponse) error {
+		sendMux.Lock()
+		defer sendMux.Unlock()
+		return stream.Send(res)
+	}
+
+	// Goroutine to forward notices, such as reaper warnings, to the client
 	go func() {
-		buf := make([]byte, 1024)
 		for {
-			n, err := ptmx.Read(buf)
-			if err != nil {
-				log.Fatalf("error trying to read from PTY: %v", err)
+			select {
+			case notice := <-attachment.Notices():
+				_ = send(&pb.CommandResponse{Payload: &pb.CommandResponse_Notice{Notice: notice}})
+			case <-stream.Context().Done():
 				return
 			}
-			if n == 0 {
-				continue
+		}
+	}()
+
+	// Goroutine to send the session output to client, starting with the
+	// scrollback kept while no client was attached. It finishes once the
+	// shell exits, after reporting the exit status
+	outputDone := make(chan error, 1)
+	go func() {
+		if len(scrollback) > 0 {
+			if err := send(&pb.CommandResponse{Payload: &pb.CommandResponse_Output{Output: scrollback}}); err != nil {
+				outputDone <- err
+				return
 			}
+		}
 
-			// Send output to client
-			if err := stream.Send(&pb.CommandResponse{Output: string(buf[:n])}); err != nil {
-				log.Fatalf("error trying to send response: %v", err)
+		for {
+			select {
+			case data, ok := <-attachment.Output():
+				if !ok {
+					outputDone <- sendExitStatus(stream.Context(), send, bashSession)
+					return
+				}
+
+				// Send output to client. Failing to do so means the client is gone
+				if err := send(&pb.CommandResponse{Payload: &pb.CommandResponse_Output{Output: data}}); err != nil {
+					outputDone <- err
+					return
+				}
+			case <-attachment.Dropped():
+				slog.Warn("client fell behind the session output, detaching it", "session", sessionId, "client", clientAddress, "user", identity)
+				outputDone <- status.Error(codes.ResourceExhausted, "client fell behind the session output and was detached, reattach to resume")
+				return
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	// Goroutine to receive client commands and copy to PTY
+	inputDone := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				inputDone <- err
+				return
+			}
+
+			if err := s.handleCommandRequest(stream.Context(), bashSession, attachment, commands, req); err != nil {
+				inputDone <- err
 				return
 			}
 		}
 	}()
 
-	// Loop to receive client commands and copy to PTY
-	for {
-		req, err := stream.Recv()
+	select {
+	case err := <-outputDone:
+		return err
+	case err := <-inputDone:
 		if err == io.EOF {
-			s.sessionMux.Lock()
-			bashSession.InUse = false // Mark session as not in use
-			s.sessionMux.Unlock()
-			fmt.Printf("Marked session %s as not in use after EOF.\n", sessionId)
 			return nil
 		}
-		if err != nil {
-			return err
+		return err
+	}
+}
+
+// sendExitStatus waits for the session's shell to exit and reports its exit
+// code, or the signal that terminated it, as the last message of the stream
+func sendExitStatus(ctx context.Context, send func(*pb.CommandResponse) error, bashSession *session.BashSession) error {
+	select {
+	case <-bashSession.Exited():
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	code, signal := bashSession.ExitStatus()
+	slog.Info("session exited", "session", bashSession.Id, "code", code, "signal", signal)
+
+	return send(&pb.CommandResponse{Payload: &pb.CommandResponse_Exit{Exit: newExitStatus(code, signal)}})
+}
+
+func newExitStatus(code int, signal string) *pb.ExitStatus {
+	exitStatus := &pb.ExitStatus{Code: int32(code)}
+	if signal != "" {
+		exitStatus.Signal = &signal
+	}
+	return exitStatus
+}
+
+// attachModes maps the attach modes of the API to the session ones
+var attachModes = map[pb.AttachMode]session.Mode{
+	pb.AttachMode_WRITER:   session.Writer,
+	pb.AttachMode_OBSERVER: session.Observer,
+}
+
+// checkAttachMode resolves the attach mode of a request. Proto3 enums accept
+// any number, so unknown modes are rejected rather than read as the zero
+// value, and every mode but observing needs the shell scope.
+func checkAttachMode(ctx context.Context, mode pb.AttachMode) (session.Mode, error) {
+	resolved, ok := attachModes[mode]
+	if !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "unknown attach mode: %v", mode)
+	}
+	if resolved != session.Observer {
+		if err := auth.CheckScope(ctx, auth.ScopeShell); err != nil {
+			return 0, err
 		}
+	}
+	return resolved, nil
+}
+
+func apiAttachMode(mode session.Mode) pb.AttachMode {
+	if mode == session.Observer {
+		return pb.AttachMode_OBSERVER
+	}
+	return pb.AttachMode_WRITER
+}
+
+// handleCommandRequest applies a single stream message to the session's PTY.
+// Observers are read-only, so anything they send is rejected. The command
+// lines typed are rebuilt from the input in commands for the audit log.
+func (s *Server) handleCommandRequest(ctx context.Context, bashSession *session.BashSession, attachment *session.Attachment, commands *audit.LineBuffer, req *pb.CommandRequest) error {
+	if req.Payload != nil && attachment.Mode == session.Observer {
+		return status.Error(codes.PermissionDenied, "observers can't write to the session")
+	}
 
-		// Write received command on PTY
-		if _, err := ptmx.Write([]byte(req.Command + "\n")); err != nil {
-			return err
+	switch payload := req.Payload.(type) {
+	case *pb.CommandRequest_Input:
+		// Write received keystrokes on PTY as they are
+		if _, err := bashSession.Write(payload.Input); err != nil {
+			return sessionError(err, "write to session")
 		}
+		if s.audit != nil {
+			for _, line := range commands.Feed(payload.Input) {
+				s.audit.Event(ctx, audit.Command, "session", bashSession.Id, "command", line)
+			}
+		}
+	case *pb.CommandRequest_Resize:
+		size := payload.Resize
+		if err := bashSession.Resize(uint16(size.Rows), uint16(size.Cols), uint16(size.Width), uint16(size.Height)); err != nil {
+			return sessionError(err, "resize PTY")
+		}
+		s.audit.Event(ctx, audit.SessionResize, "session", bashSession.Id, "cols", size.Cols, "rows", size.Rows)
+	case *pb.CommandRequest_Signal:
+		sig, ok := signals[payload.Signal]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "unsupported signal: %v", payload.Signal)
+		}
+		if err := bashSession.Signal(sig); err != nil {
+			return sessionError(err, "signal session")
+		}
+		s.audit.Event(ctx, audit.SessionSignal, "session", bashSession.Id, "signal", payload.Signal.String())
 	}
+	return nil
+}
+
+// sessionError converts an error of a session operation to a gRPC status.
+// Sessions whose shell has already ended are a precondition failure the
+// client can act on, anything else is an internal error.
+func sessionError(err error, action string) error {
+	if errors.Is(err, session.ErrSessionEnded) {
+		return status.Errorf(codes.FailedPrecondition, "failed to %s: %v", action, err)
+	}
+	return status.Errorf(codes.Internal, "failed to %s: %v", action, err)
+}
+
+// signals maps the signals of the API to the ones delivered on the server.
+// SIGNAL_UNSPECIFIED has no entry, so a request that leaves it unset fails.
+var signals = map[pb.Signal]syscall.Signal{
+	pb.Signal_SIGINT:  syscall.SIGINT,
+	pb.Signal_SIGTERM: syscall.SIGTERM,
+	pb.Signal_SIGHUP:  syscall.SIGHUP,
+	pb.Signal_SIGKILL: syscall.SIGKILL,
+	pb.Signal_SIGTSTP: syscall.SIGTSTP,
+}
+
+func (s *Server) SignalSession(ctx context.Context, req *pb.SignalRequest) (*pb.SessionResponse, error) {
+	sig, ok := signals[req.Signal]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported signal: %v", req.Signal)
+	}
+
+	s.sessionMux.Lock()
+	bashSession, ok := s.sessions[req.Id]
+	s.sessionMux.Unlock()
+	if !ok {
+		return &pb.SessionResponse{
+			Id:            req.Id,
+			SessionStatus: pb.SessionStatus_TERMINATED,
+		}, nil
+	}
+	if err := s.authorizeSession(ctx, bashSession, pb.AttachMode_WRITER); err != nil {
+		return nil, err
+	}
+
+	if err := bashSession.Signal(sig); err != nil {
+		return nil, sessionError(err, "signal session")
+	}
+	slog.Info("session signalled", "session", req.Id, "signal", req.Signal, "user", auth.Name(ctx))
+	s.audit.Event(ctx, audit.SessionSignal, "session", req.Id, "signal", req.Signal.String())
+
+	return &pb.SessionResponse{
+		Id:            req.Id,
+		SessionStatus: sessionStatus(bashSession),
+	}, nil
 }
 
 func (s *Server) MakeSessionAvailable(ctx context.Context, req *pb.SessionRequest) (*pb.SessionResponse, error) {
@@ -168,19 +511,43 @@ func (s *Server) MakeSessionAvailable(ctx context.Context, req *pb.SessionReques
 	s.sessionMux.Lock()
 	defer s.sessionMux.Unlock()
 
-	if session, ok := s.sessions[*sessionId]; ok {
-		if session.Ptmx != nil {
-			if err := session.Ptmx.Close(); err != nil {
-				return nil, fmt.Errorf("failed to close PTY: %v", err)
-			}
+	if oldSession, ok := s.sessions[*sessionId]; ok {
+		if err := s.authorizeSession(ctx, oldSession, pb.AttachMode_WRITER); err != nil {
+			return nil, err
+		}
 
-			newSession, _ := session.New(*sessionId)
-			newSession.InUse = false
+		if oldSession.Ptmx != nil {
+			recording, err := s.startRecording(ownerName(oldSession), *sessionId)
+			if err != nil {
+				return nil, err
+			}
+			_, span := tracing.Start(ctx, "session.spawn", trace.WithAttributes(attribute.String("session.id", *sessionId)))
+			newSession, err := oldSession.New(*sessionId, session.Options{
+				Command:   oldSession.Command,
+				Account:   oldSession.Account,
+				Recording: recording,
+			})
+			tracing.End(span, err)
+			if err != nil {
+				s.metrics.spawnFailures.Inc()
+				return nil, status.Errorf(codes.Internal, "failed to start a new session: %v", err)
+			}
+			newSession.SharedWrite = oldSession.SharedWrite
+			newSession.Owner = oldSession.Owner
+			newSession.Viewers = oldSession.Viewers
 			s.sessions[*sessionId] = newSession
+			go s.removeOnExit(newSession)
+
+			// Kill and reap the previous shell without holding the lock during its grace period
+			_, span = tracing.Start(ctx, "session.teardown", trace.WithAttributes(attribute.String("session.id", *sessionId)))
+			go func() {
+				oldSession.Terminate(terminateGracePeriod)
+				span.End()
+			}()
 		}
 
-		fmt.Printf("Session liberated for use: %s", *sessionId)
-		fmt.Printf("%v", s.sessions[*sessionId])
+		slog.Info("session made available", "session", *sessionId, "user", auth.Name(ctx))
+		s.audit.Event(ctx, audit.SessionCreate, "session", *sessionId, "owner", oldSession.Owner, "replaces", true)
 		return &pb.SessionResponse{
 			Id:            *sessionId,
 			SessionStatus: pb.SessionStatus_AVAILABLE,
@@ -193,11 +560,159 @@ func (s *Server) MakeSessionAvailable(ctx context.Context, req *pb.SessionReques
 	}, nil
 }
 
+// TerminateSession kills the session's shell, reaps it and forgets the session.
+// Like the other session RPCs, it reports an unknown session as terminated,
+// so terminating twice isn't an error.
+func (s *Server) TerminateSession(ctx context.Context, req *pb.SessionRequest) (*pb.SessionResponse, error) {
+	sessionId := req.GetId()
+
+	s.sessionMux.Lock()
+	bashSession, ok := s.sessions[sessionId]
+	if !ok {
+		s.sessionMux.Unlock()
+		return &pb.SessionResponse{
+			Id:            sessionId,
+			SessionStatus: pb.SessionStatus_TERMINATED,
+		}, nil
+	}
+	if err := s.authorizeSession(ctx, bashSession, pb.AttachMode_WRITER); err != nil {
+		s.sessionMux.Unlock()
+		return nil, err
+	}
+	delete(s.sessions, sessionId)
+	s.sessionMux.Unlock()
+
+	_, span := tracing.Start(ctx, "session.teardown", trace.WithAttributes(attribute.String("session.id", sessionId)))
+	code, signal := bashSession.Terminate(terminateGracePeriod)
+	span.SetAttributes(attribute.Int("session.exit_code", code))
+	span.End()
+	slog.Info("session terminated", "session", sessionId, "code", code, "user", auth.Name(ctx))
+	s.audit.Event(ctx, audit.SessionTerminate, "session", sessionId, "owner", bashSession.Owner, "code", code, "signal", signal)
+
+	return &pb.SessionResponse{
+		Id:            sessionId,
+		SessionStatus: pb.SessionStatus_TERMINATED,
+		ExitStatus:    newExitStatus(code, signal),
+	}, nil
+}
+
+// sessionStatus reports a session as in use while any client is attached
+func sessionStatus(bashSession *session.BashSession) pb.SessionStatus {
+	if bashSession.InUse() {
+		return pb.SessionStatus_IN_USE
+	}
+	return pb.SessionStatus_AVAILABLE
+}
+
+// sessionList returns the current sessions. Anything that takes a session's
+// own locks works on this copy, since sessionMux must never wait on a session.
+func (s *Server) sessionList() []*session.BashSession {
+	s.sessionMux.Lock()
+	defer s.sessionMux.Unlock()
+
+	sessions := make([]*session.BashSession, 0, len(s.sessions))
+	for _, bashSession := range s.sessions {
+		sessions = append(sessions, bashSession)
+	}
+	return sessions
+}
+
+func (s *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	// Clients only see the sessions they could observe
+	res := &pb.ListSessionsResponse{}
+	for _, bashSession := range s.sessionList() {
+		if s.authorizeSession(ctx, bashSession, pb.AttachMode_OBSERVER) != nil {
+			continue
+		}
+
+		info := &pb.SessionInfo{
+			Id:            bashSession.Id,
+			SessionStatus: sessionStatus(bashSession),
+			CreatedAt:     timestamppb.New(bashSession.CreatedAt),
+			LastActivity:  timestamppb.New(bashSession.LastActivity()),
+			ShellPid:      int32(bashSession.TerminalCommand.Process.Pid),
+			SharedWrite:   bashSession.SharedWrite,
+			Owner:         ownerName(bashSession),
+			Viewers:       bashSession.Viewers,
+		}
+
+		for _, attachment := range bashSession.Attachments() {
+			info.Attachments = append(info.Attachments, &pb.Attachment{
+				ClientAddress: attachment.ClientAddress,
+				Identity:      attachment.Identity,
+				Mode:          apiAttachMode(attachment.Mode),
+				AttachedAt:    timestamppb.New(attachment.AttachedAt),
+			})
+		}
+
+		// The shell may exit at any time, so these are best effort
+		if command, err := bashSession.ForegroundCommand(); err == nil {
+			info.ForegroundCommand = command
+		}
+		if size, err := bashSession.Size(); err == nil {
+			info.Size = &pb.WindowSize{
+				Rows:   uint32(size.Row),
+				Cols:   uint32(size.Col),
+				Width:  uint32(size.Xpixel),
+				Height: uint32(size.Ypixel),
+			}
+		}
+
+		res.Sessions = append(res.Sessions, info)
+	}
+
+	sort.Slice(res.Sessions, func(i, j int) bool {
+		return res.Sessions[i].CreatedAt.AsTime().Before(res.Sessions[j].CreatedAt.AsTime())
+	})
+
+	return res, nil
+}
+
 func main() {
+	pflag.Parse()
+
+	switch pflag.Arg(0) {
+	case "init":
+		hosts, _ := pflag.CommandLine.GetStringSlice("hosts")
+		force, _ := pflag.CommandLine.GetBool("force")
+		if err := runInit(hosts, force); err != nil {
+			fmt.Fprintf(os.Stderr, "init failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	case "issue":
+		outDir, _ := pflag.CommandLine.GetString("out")
+		if err := runIssue(pflag.Arg(1), outDir); err != nil {
+			fmt.Fprintf(os.Stderr, "issue failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	logger, err := newLogger(environment.LogFormat, environment.LogLevel)
+	if err != nil {
+		panic(err)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "gssh-server",
+		Endpoint:    environment.TracingEndpoint,
+		Insecure:    environment.TracingInsecure,
+		SampleRatio: environment.TracingSampleRatio,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdownTracing(ctx)
+	}()
+
 	port := viper.GetInt("port")
 
 	address := fmt.Sprintf("%s:%d", environment.ServerAddress, port)
-	fmt.Printf("Starting server on address: %s...\n", address)
 
 	socket, err := net.Listen("tcp", address)
 	if err != nil {
@@ -205,31 +720,166 @@ func main() {
 	}
 	defer socket.Close()
 
-	creds, err := credentials.NewServerTLSFromFile(crt, key)
+	tlsConfig, renewer, err := newServerTLSConfig()
 	if err != nil {
 		panic(err)
 	}
+	go renewer.Run(context.Background())
+	creds := credentials.NewTLS(tlsConfig)
 
-	fmt.Printf("Listening on %s with TLS...\n", address)
+	slog.Info("listening with TLS", "address", address)
 
-	// Combine ServerAddress and ServerCertPort to create certAddress
-	certPortStr := strconv.Itoa(environment.ServerCertPort)
-	certAddress := environment.ServerAddress + ":" + certPortStr
-
-	// Serve the certificate via HTTP
-	http.HandleFunc("/cert", func(w http.ResponseWriter, r *http.Request) { http.ServeFile(w, r, "./cert/server.crt") })
-	go http.ListenAndServe(certAddress, nil)
+	session.ScrollbackSize = environment.SessionScrollback
 
 	server := &Server{
-		sessions: make(map[string]*session.BashSession),
+		sessions:    make(map[string]*session.BashSession),
+		idleTimeout: environment.SessionIdleTimeout,
+		maxAge:      environment.SessionMaxAge,
+		warning:     environment.SessionWarning,
+		maxSessions: environment.MaxSessions,
+
+		sessionTokens:   auth.NewSessionTokens(environment.AuthTokenTTL),
+		runAsUser:       environment.RunAsUser,
+		permitRootLogin: environment.PermitRootLogin,
+		recordingsDir:   environment.RecordingsDir,
 	}
-
-	s := grpc.NewServer(grpc.Creds(creds))
+	server.metrics = newMetrics(server)
+	server.health = health.NewServer()
+	server.audit, err = audit.New(audit.Options{
+		File:         environment.AuditLogFile,
+		MaxSize:      int64(environment.AuditLogMaxSize) << 20,
+		MaxBackups:   environment.AuditLogMaxBackups,
+		Syslog:       environment.AuditSyslog,
+		SyslogSocket: environment.AuditSyslogSocket,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer server.audit.Close()
+	if server.runAsUser {
+		server.userMap, err = parseUserMap(environment.UserMap)
+		if err != nil {
+			panic(err)
+		}
+	}
+	if environment.AuthorizedKeysFile != "" {
+		server.publicKeys, err = auth.NewPublicKeyAuthenticator(environment.AuthorizedKeysFile)
+		if err != nil {
+			panic(err)
+		}
+	}
+	// API tokens carry their own scopes, other users are admins when listed
+	authenticators := []auth.Authenticator{auth.WithAdmins(environment.AdminUsers, server.sessionTokens.Authenticator())}
+	if environment.APITokensFile != "" {
+		server.apiTokens, err = auth.LoadAPITokens(environment.APITokensFile)
+		if err != nil {
+			panic(err)
+		}
+		if err := server.apiTokens.Watch(context.Background()); err != nil {
+			panic(err)
+		}
+		authenticators = append(authenticators, server.apiTokens.Authenticator())
+	}
+	authenticators = append(authenticators, auth.WithAdmins(environment.AdminUsers, auth.CertificateAuthenticator(environment.TLSClientIdentity)))
+	for i, authenticator := range authenticators {
+		authenticators[i] = auditAuthenticator(server.audit, authenticator)
+	}
+	go server.reap()
+
+	// Once public key or API token authentication is enabled, every RPC but
+	// the authentication itself and health checks needs an identity
+	server.authRequired = server.publicKeys != nil || server.apiTokens != nil
+	unaryAuth, streamAuth := auth.Interceptors(
+		server.authRequired,
+		[]string{
+			pb.TerminalService_Authenticate_FullMethodName,
+			healthpb.Health_Check_FullMethodName,
+			healthpb.Health_Watch_FullMethodName,
+		},
+		authenticators...,
+	)
+	unaryScope, streamScope := auth.ScopeInterceptors(methodScopes)
+
+	s := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
+		grpc.StatsHandler(loginTracker{}),
+		grpc.ChainUnaryInterceptor(server.metrics.unaryInterceptor, unaryRecovery, unaryAuth, unaryScope),
+		grpc.ChainStreamInterceptor(server.metrics.streamInterceptor, streamRecovery, streamAuth, streamScope),
+	)
 	pb.RegisterTerminalServiceServer(s, server)
+	healthpb.RegisterHealthServer(s, server.health)
+	reflection.Register(s)
+	go server.monitorHealth(renewer, environment.HealthCertMinValidity)
+
+	// The HTTPS endpoints live and die with the gRPC server
+	mux := http.NewServeMux()
+	certAddress := environment.ServerAddress + ":" + strconv.Itoa(environment.ServerCertPort)
+	if environment.BootstrapEnabled {
+		mux.HandleFunc("GET /bundle", serveTrustBundle)
+		slog.Info("serving trust bundle", "url", "https://"+certAddress+"/bundle")
+	}
+	if environment.MetricsEnabled {
+		// The metrics tell who runs what, so they need a client certificate
+		if tlsConfig.ClientCAs == nil {
+			panic("METRICS_ENABLED needs TLS_CLIENT_CA to verify the client certificates of scrapers")
+		}
+		mux.Handle("GET /metrics", server.metrics.handler())
+		slog.Info("serving metrics", "url", "https://"+certAddress+"/metrics")
+	}
+	var httpServer *http.Server
+	httpErr := make(chan error, 1)
+	if environment.BootstrapEnabled || environment.MetricsEnabled {
+		httpServer = newHTTPServer(certAddress, renewer, tlsConfig.ClientCAs, mux)
+		go func() {
+			if err := httpServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				slog.Error("HTTPS server failed", "error", err)
+				httpErr <- err
+				s.Stop()
+			}
+		}()
+	}
 
-	fmt.Println("Serving gRPC...")
+	// SIGTERM and SIGINT shut the server down gracefully, SIGUSR1 starts
+	// draining it and SIGUSR2 stops draining
+	var shuttingDown atomic.Bool
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT, syscall.SIGUSR1, syscall.SIGUSR2)
+		for sig := range sigs {
+			switch sig {
+			case syscall.SIGUSR1:
+				server.setDraining(true)
+			case syscall.SIGUSR2:
+				server.setDraining(false)
+			default:
+				signal.Stop(sigs)
+				shuttingDown.Store(true)
+				server.shutdown(s, environment.ShutdownTimeout)
+				return
+			}
+		}
+	}()
 
-	if err := s.Serve(socket); err != nil {
+	err = s.Serve(socket)
+	if shuttingDown.Load() {
+		// Serve returns as soon as shutdown begins, wait for it to finish.
+		// Draining alone doesn't stop Serve, so it isn't waited for.
+		<-shutdownDone
+	}
+	if httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(ctx)
+	}
+	if err != nil {
+		panic(err)
+	}
+	select {
+	case err := <-httpErr:
 		panic(err)
+	default:
 	}
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "gSSH/pb"
+	"gSSH/pkg/session"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnknownSession checks that every RPC acting on a session by its ID
+// reports an unknown one the same way
+func TestUnknownSession(t *testing.T) {
+	server := &Server{sessions: make(map[string]*session.BashSession)}
+	ctx := context.Background()
+	id := "no-such-session"
+
+	for _, test := range []struct {
+		rpc  string
+		call func() (*pb.SessionResponse, error)
+	}{
+		{"SignalSession", func() (*pb.SessionResponse, error) {
+			return server.SignalSession(ctx, &pb.SignalRequest{Id: id, Signal: pb.Signal_SIGINT})
+		}},
+		{"MakeSessionAvailable", func() (*pb.SessionResponse, error) {
+			return server.MakeSessionAvailable(ctx, &pb.SessionRequest{Id: &id})
+		}},
+		{"TerminateSession", func() (*pb.SessionResponse, error) {
+			return server.TerminateSession(ctx, &pb.SessionRequest{Id: &id})
+		}},
+	} {
+		res, err := test.call()
+		if err != nil {
+			t.Errorf("%s: %v", test.rpc, err)
+			continue
+		}
+		if res.Id != id || res.SessionStatus != pb.SessionStatus_TERMINATED {
+			t.Errorf("%s = %s %v, want %s %v", test.rpc, res.Id, res.SessionStatus, id, pb.SessionStatus_TERMINATED)
+		}
+	}
+}
+
+func TestUnspecifiedSignal(t *testing.T) {
+	server := &Server{sessions: make(map[string]*session.BashSession)}
+
+	for _, signal := range []pb.Signal{pb.Signal_SIGNAL_UNSPECIFIED, pb.Signal(42)} {
+		_, err := server.SignalSession(context.Background(), &pb.SignalRequest{Id: "any", Signal: signal})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("SignalSession(%v) = %v, want InvalidArgument", signal, err)
+		}
+		err = server.handleCommandRequest(context.Background(), nil, &session.Attachment{Mode: session.Writer}, nil, &pb.CommandRequest{
+			Payload: &pb.CommandRequest_Signal{Signal: signal},
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("in-stream signal %v = %v, want InvalidArgument", signal, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gSSH/pb"
+	"gSSH/pkg/audit"
+	"gSSH/pkg/session"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// How often shutdown checks whether every session ended
+const shutdownPollInterval = 500 * time.Millisecond
+
+// Drain switches drain mode on or off. Draining servers refuse new sessions
+// but keep the existing ones, so they can be restarted once those ended.
+func (s *Server) Drain(ctx context.Context, req *pb.DrainRequest) (*pb.DrainResponse, error) {
+	s.setDraining(req.Enabled)
+
+	return &pb.DrainResponse{
+		Draining: s.draining.Load(),
+		Sessions: int32(s.sessionCount()),
+	}, nil
+}
+
+func (s *Server) setDraining(draining bool) {
+	if s.draining.Swap(draining) == draining {
+		return
+	}
+	if draining {
+		slog.Info("draining, new sessions are refused", "sessions", s.sessionCount())
+	} else {
+		slog.Info("stopped draining, new sessions are accepted again")
+	}
+	s.updateHealth()
+}
+
+func (s *Server) sessionCount() int {
+	s.sessionMux.Lock()
+	defer s.sessionMux.Unlock()
+	return len(s.sessions)
+}
+
+// shutdown stops the server gracefully: it stops accepting connections,
+// warns the attached clients and gives sessions the timeout to end on their
+// own, then terminates the remaining ones and waits for every RPC to finish
+func (s *Server) shutdown(grpcServer *grpc.Server, timeout time.Duration) {
+	s.setDraining(true)
+	s.health.Shutdown()
+	slog.Info("shutting down", "timeout", timeout, "sessions", s.sessionCount())
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	for _, bashSession := range s.sessionList() {
+		bashSession.Warn(fmt.Sprintf("server is shutting down, session will be closed in %s", timeout.Round(time.Second)))
+	}
+
+	deadline := time.Now().Add(timeout)
+	for s.sessionCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(shutdownPollInterval)
+	}
+
+	// Hang up whatever is still running and reap it
+	s.sessionMux.Lock()
+	remaining := make([]*session.BashSession, 0, len(s.sessions))
+	for id, bashSession := range s.sessions {
+		remaining = append(remaining, bashSession)
+		delete(s.sessions, id)
+	}
+	s.sessionMux.Unlock()
+
+	var wg sync.WaitGroup
+	for _, bashSession := range remaining {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, signal := bashSession.Terminate(terminateGracePeriod)
+			slog.Info("session terminated", "session", bashSession.Id, "code", code)
+			s.audit.Event(context.Background(), audit.SessionTerminate, "session", bashSession.Id, "owner", bashSession.Owner, "reason", "shutdown", "code", code, "signal", signal)
+		}()
+	}
+	wg.Wait()
+
+	// Streams end once their session's exit status is sent, anything still
+	// hanging after that is cut off
+	select {
+	case <-stopped:
+	case <-time.After(terminateGracePeriod):
+		slog.Warn("RPCs still running after shutdown, closing them")
+		grpcServer.Stop()
+	}
+}
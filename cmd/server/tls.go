@@ -0,0 +1,57 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"gSSH/pkg/pki"
+	"os"
+)
+
+// newServerTLSConfig loads the server certificate and, when a client CA bundle
+// is configured, verifies client certificates against it. The certificate is
+// served by the returned renewer, which renews it when the CA key is around.
+func newServerTLSConfig() (*tls.Config, *pki.Renewer, error) {
+	renewer := &pki.Renewer{
+		CertFile:    environment.TLSCert,
+		KeyFile:     environment.TLSKey,
+		RenewBefore: environment.TLSRenewBefore,
+	}
+	if err := renewer.Load(); err != nil {
+		return nil, nil, fmt.Errorf("failed to load server certificate, run \"server init\" to create one: %v", err)
+	}
+
+	if _, err := os.Stat(environment.TLSCAKey); err == nil {
+		ca, err := pki.LoadCA(environment.TLSCACert, environment.TLSCAKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		renewer.CA = ca
+	}
+
+	config := &tls.Config{GetCertificate: renewer.GetCertificate}
+
+	if environment.TLSClientCA == "" {
+		return config, renewer, nil
+	}
+
+	bundle, err := os.ReadFile(environment.TLSClientCA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read client CA bundle: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if ok := clientCAs.AppendCertsFromPEM(bundle); !ok {
+		return nil, nil, fmt.Errorf("no certificates found in client CA bundle %s", environment.TLSClientCA)
+	}
+	config.ClientCAs = clientCAs
+
+	switch environment.TLSClientAuth {
+	case "require":
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	case "optional":
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		return nil, nil, fmt.Errorf("unknown TLS_CLIENT_AUTH: %s", environment.TLSClientAuth)
+	}
+	return config, renewer, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	pb "gSSH/pb"
+	"gSSH/pkg/session"
+	"gSSH/pkg/tracing/tracingtest"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// TestSessionSpans checks that spawning and tearing down a shell are traced
+// as children of the RPC that asked for it, wherever the RPC came from
+func TestSessionSpans(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+
+	server := &Server{sessions: make(map[string]*session.BashSession)}
+	server.metrics = newMetrics(server)
+
+	listener := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	pb.RegisterTerminalServiceServer(s, server)
+	go s.Serve(listener)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewTerminalServiceClient(conn)
+
+	ctx := context.Background()
+	created, err := client.RequestSession(ctx, &pb.SessionRequest{})
+	if err != nil {
+		t.Fatalf("RequestSession: %v", err)
+	}
+	terminated, err := client.TerminateSession(ctx, &pb.SessionRequest{Id: &created.Id})
+	if err != nil {
+		t.Fatalf("TerminateSession: %v", err)
+	}
+	if terminated.SessionStatus != pb.SessionStatus_TERMINATED {
+		t.Errorf("TerminateSession status = %v, want %v", terminated.SessionStatus, pb.SessionStatus_TERMINATED)
+	}
+
+	spans := recorder.Spans()
+
+	for _, test := range []struct {
+		span string
+		rpc  string
+	}{
+		{"session.spawn", pb.TerminalService_RequestSession_FullMethodName},
+		{"session.teardown", pb.TerminalService_TerminateSession_FullMethodName},
+	} {
+		span := tracingtest.Find(t, spans, test.span, trace.SpanKindInternal)
+		clientRPC := tracingtest.Find(t, spans, test.rpc[1:], trace.SpanKindClient)
+		serverRPC := tracingtest.Find(t, spans, test.rpc[1:], trace.SpanKindServer)
+
+		if span.SpanContext.TraceID() != clientRPC.SpanContext.TraceID() {
+			t.Errorf("%s is in trace %s, want the client's %s", test.span, span.SpanContext.TraceID(), clientRPC.SpanContext.TraceID())
+		}
+		if span.Parent.SpanID() != serverRPC.SpanContext.SpanID() {
+			t.Errorf("%s's parent is %s, want the %s span %s", test.span, span.Parent.SpanID(), test.rpc, serverRPC.SpanContext.SpanID())
+		}
+		if serverRPC.Parent.SpanID() != clientRPC.SpanContext.SpanID() {
+			t.Errorf("%s server span's parent is %s, want the client span %s", test.rpc, serverRPC.Parent.SpanID(), clientRPC.SpanContext.SpanID())
+		}
+		if !hasAttribute(span, "session.id", created.Id) {
+			t.Errorf("%s has no session.id %s: %v", test.span, created.Id, span.Attributes)
+		}
+	}
+}
+
+func hasAttribute(span tracetest.SpanStub, key, value string) bool {
+	for _, attribute := range span.Attributes {
+		if string(attribute.Key) == key && attribute.Value.AsString() == value {
+			return true
+		}
+	}
+	return false
+}
@@ -22,6 +22,12 @@ const (
 	TerminalService_ExecuteCommand_FullMethodName       = "/container.TerminalService/ExecuteCommand"
 	TerminalService_RequestSession_FullMethodName       = "/container.TerminalService/RequestSession"
 	TerminalService_MakeSessionAvailable_FullMethodName = "/container.TerminalService/MakeSessionAvailable"
+	TerminalService_Exec_FullMethodName                 = "/container.TerminalService/Exec"
+	TerminalService_SignalSession_FullMethodName        = "/container.TerminalService/SignalSession"
+	TerminalService_ListSessions_FullMethodName         = "/container.TerminalService/ListSessions"
+	TerminalService_TerminateSession_FullMethodName     = "/container.TerminalService/TerminateSession"
+	TerminalService_Authenticate_FullMethodName         = "/container.TerminalService/Authenticate"
+	TerminalService_Drain_FullMethodName                = "/container.TerminalService/Drain"
 )
 
 // TerminalServiceClient is the client API for TerminalService service.
@@ -31,6 +37,12 @@ type TerminalServiceClient interface {
 	ExecuteCommand(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CommandRequest, CommandResponse], error)
 	RequestSession(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*SessionResponse, error)
 	MakeSessionAvailable(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*SessionResponse, error)
+	Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ExecRequest, ExecResponse], error)
+	SignalSession(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SessionResponse, error)
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	TerminateSession(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*SessionResponse, error)
+	Authenticate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AuthRequest, AuthResponse], error)
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
 }
 
 type terminalServiceClient struct {
@@ -74,6 +86,72 @@ func (c *terminalServiceClient) MakeSessionAvailable(ctx context.Context, in *Se
 	return out, nil
 }
 
+func (c *terminalServiceClient) Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ExecRequest, ExecResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TerminalService_ServiceDesc.Streams[1], TerminalService_Exec_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecRequest, ExecResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TerminalService_ExecClient = grpc.BidiStreamingClient[ExecRequest, ExecResponse]
+
+func (c *terminalServiceClient) SignalSession(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionResponse)
+	err := c.cc.Invoke(ctx, TerminalService_SignalSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, TerminalService_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) TerminateSession(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*SessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionResponse)
+	err := c.cc.Invoke(ctx, TerminalService_TerminateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terminalServiceClient) Authenticate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AuthRequest, AuthResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TerminalService_ServiceDesc.Streams[2], TerminalService_Authenticate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AuthRequest, AuthResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TerminalService_AuthenticateClient = grpc.BidiStreamingClient[AuthRequest, AuthResponse]
+
+func (c *terminalServiceClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainResponse)
+	err := c.cc.Invoke(ctx, TerminalService_Drain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TerminalServiceServer is the server API for TerminalService service.
 // All implementations must embed UnimplementedTerminalServiceServer
 // for forward compatibility.
@@ -81,6 +159,12 @@ type TerminalServiceServer interface {
 	ExecuteCommand(grpc.BidiStreamingServer[CommandRequest, CommandResponse]) error
 	RequestSession(context.Context, *SessionRequest) (*SessionResponse, error)
 	MakeSessionAvailable(context.Context, *SessionRequest) (*SessionResponse, error)
+	Exec(grpc.BidiStreamingServer[ExecRequest, ExecResponse]) error
+	SignalSession(context.Context, *SignalRequest) (*SessionResponse, error)
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	TerminateSession(context.Context, *SessionRequest) (*SessionResponse, error)
+	Authenticate(grpc.BidiStreamingServer[AuthRequest, AuthResponse]) error
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
 	mustEmbedUnimplementedTerminalServiceServer()
 }
 
@@ -100,6 +184,24 @@ func (UnimplementedTerminalServiceServer) RequestSession(context.Context, *Sessi
 func (UnimplementedTerminalServiceServer) MakeSessionAvailable(context.Context, *SessionRequest) (*SessionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MakeSessionAvailable not implemented")
 }
+func (UnimplementedTerminalServiceServer) Exec(grpc.BidiStreamingServer[ExecRequest, ExecResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
+func (UnimplementedTerminalServiceServer) SignalSession(context.Context, *SignalRequest) (*SessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignalSession not implemented")
+}
+func (UnimplementedTerminalServiceServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedTerminalServiceServer) TerminateSession(context.Context, *SessionRequest) (*SessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TerminateSession not implemented")
+}
+func (UnimplementedTerminalServiceServer) Authenticate(grpc.BidiStreamingServer[AuthRequest, AuthResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
+}
+func (UnimplementedTerminalServiceServer) Drain(context.Context, *DrainRequest) (*DrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
 func (UnimplementedTerminalServiceServer) mustEmbedUnimplementedTerminalServiceServer() {}
 func (UnimplementedTerminalServiceServer) testEmbeddedByValue()                         {}
 
@@ -164,6 +266,92 @@ func _TerminalService_MakeSessionAvailable_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TerminalService_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TerminalServiceServer).Exec(&grpc.GenericServerStream[ExecRequest, ExecResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TerminalService_ExecServer = grpc.BidiStreamingServer[ExecRequest, ExecResponse]
+
+func _TerminalService_SignalSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).SignalSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_SignalSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).SignalSession(ctx, req.(*SignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_TerminateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).TerminateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_TerminateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).TerminateSession(ctx, req.(*SessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerminalService_Authenticate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TerminalServiceServer).Authenticate(&grpc.GenericServerStream[AuthRequest, AuthResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TerminalService_AuthenticateServer = grpc.BidiStreamingServer[AuthRequest, AuthResponse]
+
+func _TerminalService_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerminalServiceServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TerminalService_Drain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerminalServiceServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TerminalService_ServiceDesc is the grpc.ServiceDesc for TerminalService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -179,6 +367,22 @@ var TerminalService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MakeSessionAvailable",
 			Handler:    _TerminalService_MakeSessionAvailable_Handler,
 		},
+		{
+			MethodName: "SignalSession",
+			Handler:    _TerminalService_SignalSession_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _TerminalService_ListSessions_Handler,
+		},
+		{
+			MethodName: "TerminateSession",
+			Handler:    _TerminalService_TerminateSession_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _TerminalService_Drain_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -187,6 +391,18 @@ var TerminalService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Exec",
+			Handler:       _TerminalService_Exec_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Authenticate",
+			Handler:       _TerminalService_Authenticate_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "gSSH.proto",
 }
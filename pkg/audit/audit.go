@@ -0,0 +1,139 @@
+// Package audit records who did what on the server as one JSON object per
+// event, separately from the operational logs
+package audit
+
+import (
+	"context"
+	"errors"
+	"gSSH/pkg/auth"
+	"io"
+	"log/slog"
+	"log/syslog"
+
+	"google.golang.org/grpc/peer"
+)
+
+// Events of the audit log
+const (
+	AuthSuccess      = "auth.success"
+	AuthFailure      = "auth.failure"
+	SessionCreate    = "session.create"
+	SessionAttach    = "session.attach"
+	SessionDetach    = "session.detach"
+	SessionResize    = "session.resize"
+	SessionSignal    = "session.signal"
+	SessionTerminate = "session.terminate"
+	SessionExit      = "session.exit"
+	Command          = "command"
+	Exec             = "exec"
+)
+
+// Options chooses where audit events are written
+type Options struct {
+	File       string // Log file, rotated when it grows past MaxSize bytes
+	MaxSize    int64
+	MaxBackups int
+
+	Syslog       bool   // Also send every event to the local syslog daemon
+	SyslogSocket string // Unix socket of the daemon, e.g. /dev/log
+}
+
+// Logger writes audit events. A nil Logger drops them, so callers don't have
+// to check whether auditing is enabled.
+type Logger struct {
+	logger  *slog.Logger
+	closers []io.Closer
+}
+
+// New opens the destinations of the audit log, or returns nil when there are none
+func New(options Options) (*Logger, error) {
+	var writers []io.Writer
+	var closers []io.Closer
+
+	if options.File != "" {
+		file, err := OpenRotatingFile(options.File, options.MaxSize, options.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		writers = append(writers, file)
+		closers = append(closers, file)
+	}
+
+	if options.Syslog {
+		writer, err := syslog.Dial("unixgram", options.SyslogSocket, syslog.LOG_INFO|syslog.LOG_AUTHPRIV, "gssh")
+		if err != nil {
+			for _, closer := range closers {
+				_ = closer.Close()
+			}
+			return nil, err
+		}
+		writers = append(writers, writer)
+		closers = append(closers, writer)
+	}
+
+	if len(writers) == 0 {
+		return nil, nil
+	}
+
+	handler := slog.NewJSONHandler(fanout(writers), &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			// Every record is an event, its level and message say the same
+			switch attr.Key {
+			case slog.LevelKey:
+				return slog.Attr{}
+			case slog.MessageKey:
+				attr.Key = "event"
+			case slog.TimeKey:
+				attr.Value = slog.TimeValue(attr.Value.Time().UTC())
+			}
+			return attr
+		},
+	})
+	return &Logger{logger: slog.New(handler), closers: closers}, nil
+}
+
+// Event records that event happened, with the address and identity of the
+// client behind ctx and the given key/value pairs, e.g. the session ID. The
+// time is added by the logger.
+func (l *Logger) Event(ctx context.Context, event string, args ...any) {
+	if l == nil {
+		return
+	}
+
+	// Events without a client, such as reaping a session, are the server's own
+	client, user := "", "server"
+	if p, ok := peer.FromContext(ctx); ok {
+		client, user = p.Addr.String(), auth.Name(ctx)
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		user = identity.Name
+	}
+	attrs := append([]any{"client", client, "user", user}, args...)
+	l.logger.Log(context.Background(), slog.LevelInfo, event, attrs...)
+}
+
+// Close closes the log file and the syslog connection
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	var errs []error
+	for _, closer := range l.closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// fanout writes each record to every destination, a failing one doesn't
+// keep the record from the others
+type fanout []io.Writer
+
+func (f fanout) Write(p []byte) (int, error) {
+	var errs []error
+	for _, w := range f {
+		if _, err := w.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
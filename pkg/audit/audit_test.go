@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"gSSH/pkg/auth"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/peer"
+)
+
+func TestEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := New(Options{File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 4242}})
+	alice := auth.NewContext(client, &auth.Identity{Name: "alice", Method: "mtls"})
+
+	logger.Event(alice, SessionCreate, "session", "build", "command", "make")
+	logger.Event(client, AuthFailure, "error", "bad token")
+	logger.Event(context.Background(), SessionExit, "session", "build", "code", 2)
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	want := []map[string]any{
+		{"event": SessionCreate, "client": "192.0.2.1:4242", "user": "alice", "session": "build", "command": "make"},
+		{"event": AuthFailure, "client": "192.0.2.1:4242", "user": "anonymous", "error": "bad token"},
+		{"event": SessionExit, "client": "", "user": "server", "session": "build", "code": float64(2)},
+	}
+	scanner := bufio.NewScanner(file)
+	for i := 0; scanner.Scan(); i++ {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("record %s: %v", scanner.Text(), err)
+		}
+		if i >= len(want) {
+			t.Fatalf("unexpected record %s", scanner.Text())
+		}
+
+		// Times are in UTC and there is no level, every record is an event
+		recorded, err := time.Parse(time.RFC3339Nano, record["time"].(string))
+		if err != nil || recorded.Location() != time.UTC {
+			t.Errorf("record %d: time %v isn't UTC", i, record["time"])
+		}
+		delete(record, "time")
+		if len(record) != len(want[i]) {
+			t.Errorf("record %d = %v, want %v", i, record, want[i])
+		}
+		for key, value := range want[i] {
+			if record[key] != value {
+				t.Errorf("record %d: %s = %v, want %v", i, key, record[key], value)
+			}
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	logger, err := New(Options{})
+	if logger != nil || err != nil {
+		t.Fatalf("New without destinations = %v, %v, want nil, nil", logger, err)
+	}
+
+	// A nil Logger drops events
+	logger.Event(context.Background(), SessionCreate)
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close = %v", err)
+	}
+}
+
+func TestSyslog(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "log")
+	daemon, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer daemon.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := New(Options{File: path, Syslog: true, SyslogSocket: socket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Event(context.Background(), SessionTerminate, "session", "build")
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4096)
+	_ = daemon.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := daemon.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := string(buf[:n])
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same JSON record goes to both, after the syslog header with the
+	// authpriv facility at info level
+	if !strings.HasPrefix(message, "<86>") {
+		t.Errorf("syslog message %q doesn't start with %q", message, "<86>")
+	}
+	if len(data) == 0 || !json.Valid(data) || !strings.HasSuffix(message, string(data)) {
+		t.Errorf("syslog message %q doesn't end with the record %q", message, data)
+	}
+}
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineLength caps the command lines kept while typing, pasted data without
+// a newline isn't a command anyway
+const maxLineLength = 4096
+
+// LineBuffer rebuilds the command lines typed into a session from its raw
+// input. It applies backspace and line kill and drops escape sequences such
+// as arrow keys, so it sees what was typed rather than what the shell made of
+// it, e.g. after tab completion or history recall.
+type LineBuffer struct {
+	line   []byte
+	escape bool // Inside an escape sequence
+	csi    bool // Inside a control sequence, ESC [ ... final byte
+	ss3    bool // Before the last byte of ESC O x, sent by some arrow keys
+}
+
+// Feed adds input to the buffer and returns the lines it completes
+func (b *LineBuffer) Feed(input []byte) []string {
+	var lines []string
+
+	for _, c := range input {
+		switch {
+		case b.csi:
+			b.csi = c < 0x40 || c > 0x7e
+		case b.ss3:
+			b.ss3 = false
+		case b.escape:
+			b.escape = false
+			b.csi = c == '['
+			b.ss3 = c == 'O'
+		case c == 0x1b:
+			b.escape = true
+		case c == '\r' || c == '\n':
+			if line := strings.TrimSpace(strings.ToValidUTF8(string(b.line), "")); line != "" {
+				lines = append(lines, line)
+			}
+			b.line = b.line[:0]
+		case c == 0x7f || c == '\b':
+			_, size := utf8.DecodeLastRune(b.line)
+			b.line = b.line[:len(b.line)-size]
+		case c == 0x03 || c == 0x15:
+			// Ctrl-C and Ctrl-U discard the line
+			b.line = b.line[:0]
+		case c == '\t' || c >= 0x20:
+			if len(b.line) < maxLineLength {
+				b.line = append(b.line, c)
+			}
+		}
+	}
+	return lines
+}
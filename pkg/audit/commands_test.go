@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLineBuffer(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		input []string // Chunks fed one after the other
+		want  []string
+	}{
+		{"one line", []string{"ls -l\r"}, []string{"ls -l"}},
+		{"newline", []string{"ls\n"}, []string{"ls"}},
+		{"several lines in a chunk", []string{"cd /tmp\rls\r"}, []string{"cd /tmp", "ls"}},
+		{"line across chunks", []string{"l", "s", "\r"}, []string{"ls"}},
+		{"no newline yet", []string{"ls"}, nil},
+		{"blank lines", []string{"\r  \r\r"}, nil},
+		{"surrounding spaces", []string{"  ls  \r"}, []string{"ls"}},
+		{"backspace", []string{"lx\x7fs\r"}, []string{"ls"}},
+		{"ctrl-h", []string{"lx\bs\r"}, []string{"ls"}},
+		{"backspace on an empty line", []string{"\x7f\x7fls\r"}, []string{"ls"}},
+		{"backspace over a multibyte rune", []string{"café\x7fe\r"}, []string{"cafe"}},
+		{"ctrl-u", []string{"rm -rf /\x15ls\r"}, []string{"ls"}},
+		{"ctrl-c", []string{"sleep\x03\r"}, nil},
+		{"arrow keys", []string{"ls\x1b[A\x1b[D -a\r"}, []string{"ls -a"}},
+		{"application mode arrow keys", []string{"ls\x1bOA\r"}, []string{"ls"}},
+		{"escape sequence across chunks", []string{"ls\x1b", "[1;5", "C\r"}, []string{"ls"}},
+		{"alt key", []string{"\x1bbls\r"}, []string{"ls"}},
+		{"other control characters", []string{"l\x01s\x04\r"}, []string{"ls"}},
+		{"tab", []string{"echo\ta\r"}, []string{"echo\ta"}},
+		{"invalid UTF-8", []string{"ls \xff\r"}, []string{"ls"}},
+	} {
+		var buffer LineBuffer
+		var lines []string
+		for _, chunk := range test.input {
+			lines = append(lines, buffer.Feed([]byte(chunk))...)
+		}
+		if !slices.Equal(lines, test.want) {
+			t.Errorf("%s: lines = %q, want %q", test.name, lines, test.want)
+		}
+	}
+}
+
+func TestLineBufferMaxLength(t *testing.T) {
+	var buffer LineBuffer
+	lines := buffer.Feed([]byte(strings.Repeat("a", 2*maxLineLength) + "\r"))
+	if len(lines) != 1 || len(lines[0]) != maxLineLength {
+		t.Errorf("got %d lines, want one of %d bytes", len(lines), maxLineLength)
+	}
+}
@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile is an append-only log file that is renamed to <path>.1 once
+// it grows past MaxSize bytes, shifting older files up to <path>.<MaxBackups>
+// and removing the oldest one
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups int
+
+	mux  sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile opens, or creates, the log file at path
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{Path: path, MaxSize: maxSize, MaxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+// Write appends p to the file, rotating it first when p doesn't fit anymore.
+// Every write is a whole record, so records never straddle two files.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.MaxSize {
+		// A file that can't be rotated keeps growing rather than losing records
+		if err := r.rotate(); err != nil && r.file == nil {
+			return 0, fmt.Errorf("failed to rotate %s: %w", r.Path, err)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) rotate() error {
+	_ = r.file.Close()
+	err := r.shift()
+	if openErr := r.open(); openErr != nil {
+		r.file = nil
+		return openErr
+	}
+	return err
+}
+
+// shift moves the current file and its backups one number up
+func (r *RotatingFile) shift() error {
+	if r.MaxBackups <= 0 {
+		return os.Remove(r.Path)
+	}
+
+	_ = os.Remove(r.backup(r.MaxBackups))
+	for i := r.MaxBackups - 1; i > 0; i-- {
+		if err := os.Rename(r.backup(i), r.backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(r.Path, r.backup(1))
+}
+
+func (r *RotatingFile) backup(i int) string {
+	return fmt.Sprintf("%s.%d", r.Path, i)
+}
+
+// Close closes the current file
+func (r *RotatingFile) Close() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// contents reads the log file and its backups, newest first, with "" for
+// files that don't exist
+func contents(t *testing.T, path string, backups int) []string {
+	t.Helper()
+
+	var files []string
+	for i := 0; i <= backups+1; i++ {
+		name := path
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", path, i)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		files = append(files, string(data))
+	}
+	return files
+}
+
+func TestRotatingFile(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		maxSize    int64
+		maxBackups int
+		existing   string // Already in the file when it is opened
+		records    []string
+		want       []string // The file, then .1, .2, ... up to one past MaxBackups
+	}{
+		{"under the limit", 10, 2, "", []string{"aaa\n", "bbb\n"}, []string{"aaa\nbbb\n", "", "", ""}},
+		{"exactly at the limit", 8, 2, "", []string{"aaa\n", "bbb\n"}, []string{"aaa\nbbb\n", "", "", ""}},
+		{"past the limit", 8, 2, "", []string{"aaa\n", "bbb\n", "c\n"}, []string{"c\n", "aaa\nbbb\n", "", ""}},
+		{"oldest backup removed", 4, 2, "", []string{"aaa\n", "bbb\n", "ccc\n", "ddd\n"}, []string{"ddd\n", "ccc\n", "bbb\n", ""}},
+		{"record bigger than the limit", 4, 2, "", []string{"aaa\n", "big record\n", "b\n"}, []string{"b\n", "big record\n", "aaa\n", ""}},
+		{"size of an existing file counts", 8, 2, "aaaaaa\n", []string{"bbb\n"}, []string{"bbb\n", "aaaaaa\n", "", ""}},
+		{"no backups", 4, 0, "", []string{"aaa\n", "bbb\n"}, []string{"bbb\n", ""}},
+		{"no limit", 0, 2, "", []string{"aaa\n", "bbb\n", "ccc\n"}, []string{"aaa\nbbb\nccc\n", "", "", ""}},
+	} {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		if test.existing != "" {
+			if err := os.WriteFile(path, []byte(test.existing), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		file, err := OpenRotatingFile(path, test.maxSize, test.maxBackups)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, record := range test.records {
+			if n, err := file.Write([]byte(record)); n != len(record) || err != nil {
+				t.Errorf("%s: Write(%q) = %d, %v", test.name, record, n, err)
+			}
+		}
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := contents(t, path, test.maxBackups); strings.Join(got, "|") != strings.Join(test.want, "|") {
+			t.Errorf("%s: files = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRotatingFileClosed(t *testing.T) {
+	file, err := OpenRotatingFile(filepath.Join(t.TempDir(), "audit.log"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+	if _, err := file.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Errorf("Write after Close = %v, want %v", err, os.ErrClosed)
+	}
+}
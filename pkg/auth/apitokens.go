@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthorizationHeader is the metadata key clients send their API token in,
+// as "Bearer <token>"
+const AuthorizationHeader = "authorization"
+
+// APITokens authenticates automation by shared secrets. Only the SHA-256 of
+// each token is kept, in a file with one token per line:
+//
+//	<sha256 hex> <name> <scope>[,<scope>...]
+//
+// Blank lines and lines starting with # are ignored.
+type APITokens struct {
+	path   string
+	mux    sync.RWMutex
+	tokens map[string]*Identity // By token hash
+}
+
+// LoadAPITokens reads the token file at path
+func LoadAPITokens(path string) (*APITokens, error) {
+	t := &APITokens{path: path}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reload reads the token file again. The previous tokens stay in use when it
+// is invalid.
+func (t *APITokens) Reload() error {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return fmt.Errorf("failed to read API tokens: %v", err)
+	}
+
+	tokens, err := parseAPITokens(data)
+	if err != nil {
+		return fmt.Errorf("invalid API tokens file %s: %v", t.path, err)
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.tokens = tokens
+	return nil
+}
+
+func parseAPITokens(data []byte) (map[string]*Identity, error) {
+	tokens := make(map[string]*Identity)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected <sha256> <name> <scopes>", line)
+		}
+
+		hash := strings.ToLower(fields[0])
+		if raw, err := hex.DecodeString(hash); err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("line %d: token hash must be a hex encoded SHA-256", line)
+		}
+
+		scopes, err := ParseScopes(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		tokens[hash] = &Identity{Name: fields[1], Method: "token", Scopes: scopes}
+	}
+	return tokens, scanner.Err()
+}
+
+// Lookup returns the identity of a token
+func (t *APITokens) Lookup(token string) (*Identity, bool) {
+	sum := sha256.Sum256([]byte(token))
+
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	identity, ok := t.tokens[hex.EncodeToString(sum[:])]
+	return identity, ok
+}
+
+// Watch reloads the token file whenever it changes, until ctx is done. The
+// directory is watched rather than the file, so editors and tools that
+// replace the file are picked up too.
+func (t *APITokens) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(t.path)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-watcher.Events:
+				if filepath.Clean(event.Name) != filepath.Clean(t.path) || !event.Has(fsnotify.Write|fsnotify.Create) {
+					continue
+				}
+				if err := t.Reload(); err != nil {
+					slog.Error("keeping the previous API tokens", "error", err)
+					continue
+				}
+				slog.Info("reloaded API tokens", "file", t.path)
+			case err := <-watcher.Errors:
+				slog.Error("failed to watch API tokens", "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Authenticator identifies clients by the bearer token in their metadata
+func (t *APITokens) Authenticator() Authenticator {
+	return func(ctx context.Context) (*Identity, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok || len(md.Get(AuthorizationHeader)) == 0 {
+			return nil, nil
+		}
+
+		token, ok := strings.CutPrefix(md.Get(AuthorizationHeader)[0], "Bearer ")
+		if !ok {
+			return nil, fmt.Errorf("unsupported authorization scheme")
+		}
+
+		identity, ok := t.Lookup(strings.TrimSpace(token))
+		if !ok {
+			return nil, fmt.Errorf("invalid API token")
+		}
+		return identity, nil
+	}
+}
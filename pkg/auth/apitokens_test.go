@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseAPITokens(t *testing.T) {
+	hash := tokenHash("secret")
+
+	for _, test := range []struct {
+		name   string
+		file   string
+		scopes []Scope // of the token, when the file is valid
+		err    string
+	}{
+		{"one scope", hash + " ci exec\n", []Scope{ScopeExec}, ""},
+		{"several scopes", hash + " ci exec, observe\n", nil, "expected <sha256> <name> <scopes>"},
+		{"scope list", hash + " ci exec,observe\n", []Scope{ScopeExec, ScopeObserve}, ""},
+		{"upper case hash", strings.ToUpper(hash) + " ci admin\n", []Scope{ScopeAdmin}, ""},
+		{"comments and blank lines", "# ci\n\n  " + hash + " ci shell  \n", []Scope{ScopeShell}, ""},
+		{"missing scopes", hash + " ci\n", nil, "line 1: expected"},
+		{"unknown scope", "\n" + hash + " ci root\n", nil, `line 2: unknown scope: "root"`},
+		{"short hash", hash[:32] + " ci exec\n", nil, "line 1: token hash must be a hex encoded SHA-256"},
+		{"plain token", "secret ci exec\n", nil, "line 1: token hash must be"},
+	} {
+		tokens, err := parseAPITokens([]byte(test.file))
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		identity, ok := tokens[hash]
+		if !ok || identity.Name != "ci" || identity.Method != "token" || !slices.Equal(identity.Scopes, test.scopes) {
+			t.Errorf("%s: identity = %+v, want ci with %v", test.name, identity, test.scopes)
+		}
+	}
+}
+
+func TestAPITokensAuthenticator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte(tokenHash("secret")+" ci exec\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := LoadAPITokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticate := tokens.Authenticator()
+
+	for _, test := range []struct {
+		name          string
+		authorization []string
+		identity      string // empty for none
+		fails         bool
+	}{
+		{"no header", nil, "", false},
+		{"valid token", []string{"Bearer secret"}, "ci", false},
+		{"trailing space", []string{"Bearer secret "}, "ci", false},
+		{"wrong token", []string{"Bearer guess"}, "", true},
+		{"hash as token", []string{"Bearer " + tokenHash("secret")}, "", true},
+		{"other scheme", []string{"Basic secret"}, "", true},
+	} {
+		ctx := context.Background()
+		if test.authorization != nil {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(AuthorizationHeader, test.authorization[0]))
+		}
+
+		identity, err := authenticate(ctx)
+		if (err != nil) != test.fails {
+			t.Errorf("%s: error = %v, want failure %v", test.name, err, test.fails)
+		}
+		if name := identityName(identity); name != test.identity {
+			t.Errorf("%s: identity = %q, want %q", test.name, name, test.identity)
+		}
+	}
+
+	// An invalid file keeps the previous tokens
+	if err := os.WriteFile(path, []byte("garbage\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.Reload(); err == nil {
+		t.Error("reloading an invalid file succeeded")
+	}
+	if _, ok := tokens.Lookup("secret"); !ok {
+		t.Error("token lost after a failed reload")
+	}
+}
+
+func identityName(identity *Identity) string {
+	if identity == nil {
+		return ""
+	}
+	return identity.Name
+}
+
+func TestHasScope(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		scopes  []Scope
+		scope   Scope
+		allowed bool
+	}{
+		{"user without scopes runs commands", nil, ScopeExec, true},
+		{"user without scopes opens shells", nil, ScopeShell, true},
+		{"user without scopes isn't admin", nil, ScopeAdmin, false},
+		{"token with the scope", []Scope{ScopeExec}, ScopeExec, true},
+		{"token without the scope", []Scope{ScopeExec}, ScopeShell, false},
+		{"observe doesn't imply shell", []Scope{ScopeObserve}, ScopeShell, false},
+		{"admin has every scope", []Scope{ScopeAdmin}, ScopeShell, true},
+	} {
+		identity := &Identity{Name: "ci", Scopes: test.scopes}
+		if got := identity.HasScope(test.scope); got != test.allowed {
+			t.Errorf("%s: HasScope(%s) = %v, want %v", test.name, test.scope, got, test.allowed)
+		}
+	}
+}
+
+func TestWithAdmins(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		scopes []Scope
+		user   string
+		admin  bool
+	}{
+		{"listed user", nil, "root", true},
+		{"other user", nil, "alice", false},
+		{"listed name on a scoped token", []Scope{ScopeExec}, "root", false},
+	} {
+		identity := &Identity{Name: test.user, Scopes: test.scopes}
+		authenticate := WithAdmins([]string{"root"}, func(context.Context) (*Identity, error) { return identity, nil })
+
+		got, err := authenticate(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got.HasScope(ScopeAdmin) != test.admin {
+			t.Errorf("%s: admin = %v, want %v", test.name, !test.admin, test.admin)
+		}
+		if identity.HasScope(ScopeAdmin) && !slices.Contains(test.scopes, ScopeAdmin) {
+			t.Errorf("%s: the authenticator's identity was changed", test.name)
+		}
+	}
+}
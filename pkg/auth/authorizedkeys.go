@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// AuthorizedKey is an entry of an authorized_keys file with the options
+// gSSH understands
+type AuthorizedKey struct {
+	Key     ssh.PublicKey
+	Comment string
+	From    []string // Patterns the client address must match, if any
+	Command string   // Forced command
+	NoPTY   bool
+}
+
+// ParseAuthorizedKeys parses the content of an authorized_keys file
+func ParseAuthorizedKeys(data []byte) []*AuthorizedKey {
+	var keys []*AuthorizedKey
+	for len(bytes.TrimSpace(data)) > 0 {
+		key, comment, options, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			// ParseAuthorizedKey skips comments and invalid lines, so this
+			// only happens when no key is left
+			break
+		}
+
+		entry := &AuthorizedKey{Key: key, Comment: comment}
+		for _, option := range options {
+			name, value, _ := strings.Cut(option, "=")
+			// Only the surrounding quotes, a command may end in an escaped one
+			if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+				value = value[1 : len(value)-1]
+			}
+
+			switch strings.ToLower(name) {
+			case "from":
+				entry.From = strings.Split(value, ",")
+			case "command":
+				entry.Command = strings.ReplaceAll(value, `\"`, `"`)
+			case "no-pty":
+				entry.NoPTY = true
+			}
+		}
+
+		keys = append(keys, entry)
+		data = rest
+	}
+	return keys
+}
+
+// FindAuthorizedKey returns the entry matching key
+func FindAuthorizedKey(keys []*AuthorizedKey, key ssh.PublicKey) (*AuthorizedKey, error) {
+	marshaled := key.Marshal()
+	for _, entry := range keys {
+		if bytes.Equal(entry.Key.Marshal(), marshaled) {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("key %s is not authorized", ssh.FingerprintSHA256(key))
+}
+
+// AllowsAddress checks the client address against the from= patterns. Like
+// sshd, a negated pattern that matches rejects the address even when another
+// pattern accepts it.
+func (k *AuthorizedKey) AllowsAddress(ip net.IP) bool {
+	if len(k.From) == 0 {
+		return true
+	}
+
+	allowed := false
+	for _, pattern := range k.From {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+
+		if matchAddress(pattern, ip) {
+			if negated {
+				return false
+			}
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// matchAddress matches an IP against a CIDR block or a wildcard pattern
+func matchAddress(pattern string, ip net.IP) bool {
+	if _, network, err := net.ParseCIDR(pattern); err == nil {
+		return network.Contains(ip)
+	}
+	return matchWildcard(pattern, ip.String())
+}
+
+// matchWildcard matches s against a pattern where "*" matches any sequence
+// of characters and "?" matches exactly one
+func matchWildcard(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if matchWildcard(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return len(s) == 0
+}
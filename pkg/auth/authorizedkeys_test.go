@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestMatchWildcard(t *testing.T) {
+	for _, test := range []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", "10.0.0.1", true},
+		{"10.0.0.1", "10.0.0.1", true},
+		{"10.0.0.1", "10.0.0.10", false},
+		{"10.0.0.*", "10.0.0.10", true},
+		{"10.0.0.*", "10.0.1.10", false},
+		{"10.*.1", "10.0.0.1", true},
+		{"10.*.1", "10.0.0.2", false},
+		{"*.1", "192.168.0.1", true},
+		{"10.0.0.?", "10.0.0.7", true},
+		{"10.0.0.?", "10.0.0.77", false},
+		{"10.0.0.?", "10.0.0.", false},
+		{"?*", "", false},
+		{"*?", "a", true},
+		{"**", "anything", true},
+		{"fe80::*", "fe80::1", true},
+	} {
+		if got := matchWildcard(test.pattern, test.s); got != test.want {
+			t.Errorf("matchWildcard(%q, %q) = %v, want %v", test.pattern, test.s, got, test.want)
+		}
+	}
+}
+
+func TestAllowsAddress(t *testing.T) {
+	for _, test := range []struct {
+		from []string
+		ip   string
+		want bool
+	}{
+		{nil, "203.0.113.7", true},
+		{[]string{"10.0.0.1"}, "10.0.0.1", true},
+		{[]string{"10.0.0.1"}, "10.0.0.2", false},
+		{[]string{"10.0.0.0/8"}, "10.20.30.40", true},
+		{[]string{"10.0.0.0/8"}, "11.0.0.1", false},
+		{[]string{"192.168.1.*"}, "192.168.1.20", true},
+		{[]string{"192.168.1.*"}, "192.168.2.20", false},
+		{[]string{"2001:db8::/32"}, "2001:db8::1", true},
+		{[]string{"2001:db8::/32"}, "10.0.0.1", false},
+		{[]string{"10.0.0.1", "10.0.0.2"}, "10.0.0.2", true},
+		// A matching negation wins, whatever the order of the patterns
+		{[]string{"10.0.0.0/8", "!10.0.0.5"}, "10.0.0.5", false},
+		{[]string{"!10.0.0.5", "10.0.0.0/8"}, "10.0.0.5", false},
+		{[]string{"10.0.0.0/8", "!10.0.0.5"}, "10.0.0.6", true},
+		{[]string{"*", "!10.*"}, "10.1.2.3", false},
+		// Negations alone allow nothing
+		{[]string{"!10.0.0.5"}, "10.0.0.6", false},
+	} {
+		key := &AuthorizedKey{From: test.from}
+		if got := key.AllowsAddress(net.ParseIP(test.ip)); got != test.want {
+			t.Errorf("from=%q AllowsAddress(%s) = %v, want %v", test.from, test.ip, got, test.want)
+		}
+	}
+}
+
+func TestParseAuthorizedKeysOptions(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := string(ssh.MarshalAuthorizedKey(key))
+	line = line[:len(line)-1]
+
+	for _, test := range []struct {
+		name    string
+		options string
+		want    AuthorizedKey
+	}{
+		{"none", "", AuthorizedKey{}},
+		{"no-pty", "no-pty ", AuthorizedKey{NoPTY: true}},
+		{"upper case", "NO-PTY ", AuthorizedKey{NoPTY: true}},
+		{"command", `command="uptime" `, AuthorizedKey{Command: "uptime"}},
+		{"command with spaces", `command="tail -f /var/log/syslog" `, AuthorizedKey{Command: "tail -f /var/log/syslog"}},
+		{"command with quotes", `command="echo \"hi there\"" `, AuthorizedKey{Command: `echo "hi there"`}},
+		{"from", `from="10.0.0.0/8,!10.0.0.5" `, AuthorizedKey{From: []string{"10.0.0.0/8", "!10.0.0.5"}}},
+		{"all", `from="192.168.1.*",command="backup",no-pty `, AuthorizedKey{From: []string{"192.168.1.*"}, Command: "backup", NoPTY: true}},
+		{"unknown options", `no-agent-forwarding,restrict `, AuthorizedKey{}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			keys := ParseAuthorizedKeys([]byte("# comment\n\n" + test.options + line + " alice@laptop\n"))
+			if len(keys) != 1 {
+				t.Fatalf("parsed %d keys, want 1", len(keys))
+			}
+			got := keys[0]
+			if !reflect.DeepEqual(got.Key.Marshal(), key.Marshal()) {
+				t.Errorf("key = %s, want %s", ssh.FingerprintSHA256(got.Key), ssh.FingerprintSHA256(key))
+			}
+			if got.Comment != "alice@laptop" {
+				t.Errorf("comment = %q, want %q", got.Comment, "alice@laptop")
+			}
+			if !reflect.DeepEqual(got.From, test.want.From) || got.Command != test.want.Command || got.NoPTY != test.want.NoPTY {
+				t.Errorf("from=%q command=%q no-pty=%v, want from=%q command=%q no-pty=%v",
+					got.From, got.Command, got.NoPTY, test.want.From, test.want.Command, test.want.NoPTY)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Identity is the gSSH user an authenticated request acts as
+type Identity struct {
+	Name   string
+	Method string // How the user was authenticated, e.g. "mtls" or "token"
+
+	// Restrictions from the authorized_keys entry the user logged in with
+	ForcedCommand string // Runs instead of whatever the client asks for
+	NoPTY         bool   // Only non-interactive commands are allowed
+
+	// What an API token may do, nil when the identity isn't restricted
+	Scopes []Scope
+}
+
+type identityKey struct{}
+
+// NewContext returns a copy of ctx carrying the identity
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity attached to ctx, if any
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+// Name returns the name of the identity attached to ctx, or "anonymous"
+func Name(ctx context.Context) string {
+	if identity, ok := FromContext(ctx); ok {
+		return identity.Name
+	}
+	return "anonymous"
+}
+
+// Authenticator finds out who is behind a request. It returns a nil identity
+// when the request doesn't carry its kind of credentials, and an error when
+// it carries invalid ones.
+type Authenticator func(ctx context.Context) (*Identity, error)
+
+// Interceptors builds the unary and stream interceptors that attach the
+// identity found by the first successful authenticator to the request
+// context. When required is set, requests without an identity are rejected,
+// except for the methods listed in public.
+func Interceptors(required bool, public []string, authenticators ...Authenticator) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	isPublic := make(map[string]bool, len(public))
+	for _, method := range public {
+		isPublic[method] = true
+	}
+
+	authenticate := func(ctx context.Context, method string) (context.Context, error) {
+		for _, authenticator := range authenticators {
+			identity, err := authenticator(ctx)
+			if err != nil {
+				return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+			}
+			if identity != nil {
+				return NewContext(ctx, identity), nil
+			}
+		}
+
+		if required && !isPublic[method] {
+			return nil, status.Error(codes.Unauthenticated, "authentication required")
+		}
+		return ctx, nil
+	}
+
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+
+	return unary, stream
+}
+
+// contextStream overrides the context of a server stream, so stream
+// interceptors can pass values down to the handler
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Parts of a client certificate that can be used as the user identity
+const (
+	IdentitySubject = "subject" // Common name of the subject
+	IdentitySAN     = "san"     // First email, URI or DNS subject alternative name
+)
+
+// CertificateIdentity maps a verified client certificate to a user identity
+func CertificateIdentity(cert *x509.Certificate, source string) (*Identity, error) {
+	var name string
+	switch source {
+	case IdentitySubject:
+		name = cert.Subject.CommonName
+	case IdentitySAN:
+		switch {
+		case len(cert.EmailAddresses) > 0:
+			name = cert.EmailAddresses[0]
+		case len(cert.URIs) > 0:
+			name = cert.URIs[0].String()
+		case len(cert.DNSNames) > 0:
+			name = cert.DNSNames[0]
+		}
+	default:
+		return nil, fmt.Errorf("unknown certificate identity source: %s", source)
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("client certificate has no %s to use as identity", source)
+	}
+	return &Identity{Name: name, Method: "mtls"}, nil
+}
+
+// CertificateAuthenticator identifies clients by the certificate they
+// presented, once the TLS handshake verified it
+func CertificateAuthenticator(source string) Authenticator {
+	return func(ctx context.Context) (*Identity, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return nil, nil
+		}
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+			return nil, nil
+		}
+		return CertificateIdentity(tlsInfo.State.VerifiedChains[0][0], source)
+	}
+}
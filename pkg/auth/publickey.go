@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// PublicKeyAuthenticator verifies SSH key signatures against authorized_keys
+// files. The file path may contain %u for the user name and %h for their
+// home directory, like sshd's AuthorizedKeysFile.
+type PublicKeyAuthenticator struct {
+	KeysFile string
+}
+
+// NewPublicKeyAuthenticator checks that keysFile is a file per user. A key
+// line doesn't name the user it belongs to, so one file shared by everyone
+// would let any key in it log in as whichever user the client claims.
+func NewPublicKeyAuthenticator(keysFile string) (*PublicKeyAuthenticator, error) {
+	if !strings.Contains(keysFile, "%u") && !strings.Contains(keysFile, "%h") {
+		return nil, fmt.Errorf("authorized keys file %q must depend on the user with %%u or %%h", keysFile)
+	}
+	return &PublicKeyAuthenticator{KeysFile: keysFile}, nil
+}
+
+// Authorize finds the authorized_keys entry of the user for key, and checks
+// that the client address is allowed to use it
+func (a *PublicKeyAuthenticator) Authorize(userName string, key ssh.PublicKey, addr net.Addr) (*AuthorizedKey, error) {
+	path, err := a.keysFile(userName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorized keys of %s: %v", userName, err)
+	}
+
+	entry, err := FindAuthorizedKey(ParseAuthorizedKeys(data), key)
+	if err != nil {
+		return nil, err
+	}
+
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok && !entry.AllowsAddress(tcpAddr.IP) {
+		return nil, fmt.Errorf("key %s is not allowed from %s", ssh.FingerprintSHA256(key), tcpAddr.IP)
+	}
+	return entry, nil
+}
+
+func (a *PublicKeyAuthenticator) keysFile(userName string) (string, error) {
+	if strings.ContainsAny(userName, "/\x00") || userName == "" || userName == "." || userName == ".." {
+		return "", fmt.Errorf("invalid user name: %q", userName)
+	}
+
+	path := strings.ReplaceAll(a.KeysFile, "%u", userName)
+	if strings.Contains(path, "%h") {
+		account, err := user.Lookup(userName)
+		if err != nil {
+			return "", err
+		}
+		path = strings.ReplaceAll(path, "%h", account.HomeDir)
+	}
+	return path, nil
+}
+
+// NewNonce returns a random challenge for the client to sign
+func NewNonce() ([]byte, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// ChannelBinding is keying material exported from the TLS connection the
+// RPC of ctx runs on. Both ends of a connection derive the same bytes, and no
+// other connection does, so a signature over them can't be relayed by a
+// server the client was tricked into connecting to.
+func ChannelBinding(ctx context.Context) ([]byte, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("no connection to bind the challenge to")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, errors.New("the challenge can only be bound to a TLS connection")
+	}
+	return tlsInfo.State.ExportKeyingMaterial("gSSH auth", nil, 32)
+}
+
+// ChallengeData is what the client signs to prove it holds the private key.
+// It binds the nonce to gSSH, to the user and to the TLS connection, so the
+// signature can't be replayed elsewhere.
+func ChallengeData(userName string, nonce, binding []byte) []byte {
+	data := []byte("gSSH authentication\x00" + userName + "\x00")
+	data = append(data, nonce...)
+	return append(data, binding...)
+}
+
+// Verify checks the signature of the challenge and returns the identity of the user
+func (a *PublicKeyAuthenticator) Verify(entry *AuthorizedKey, userName string, nonce, binding []byte, signature *ssh.Signature) (*Identity, error) {
+	if err := entry.Key.Verify(ChallengeData(userName, nonce, binding), signature); err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+
+	return &Identity{
+		Name:          userName,
+		Method:        "publickey",
+		ForcedCommand: entry.Command,
+		NoPTY:         entry.NoPTY,
+	}, nil
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// tlsPair handshakes a TLS connection in memory and returns the context an
+// RPC on each end of it would have
+func tlsPair(t *testing.T, certificate tls.Certificate) (client, server context.Context) {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() {
+		clientConn.Close()
+		serverConn.Close()
+	})
+	tlsClient := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true})
+	tlsServer := tls.Server(serverConn, &tls.Config{Certificates: []tls.Certificate{certificate}})
+
+	errs := make(chan error, 1)
+	go func() { errs <- tlsServer.Handshake() }()
+	if err := tlsClient.Handshake(); err != nil {
+		t.Fatalf("client handshake: %v", err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("server handshake: %v", err)
+	}
+
+	client = peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tlsClient.ConnectionState()}})
+	server = peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tlsServer.ConnectionState()}})
+	return client, server
+}
+
+func selfSigned(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gSSH test"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestChannelBinding(t *testing.T) {
+	certificate := selfSigned(t)
+	client, server := tlsPair(t, certificate)
+	otherClient, _ := tlsPair(t, certificate)
+
+	clientBinding, err := ChannelBinding(client)
+	if err != nil {
+		t.Fatalf("client ChannelBinding: %v", err)
+	}
+	serverBinding, err := ChannelBinding(server)
+	if err != nil {
+		t.Fatalf("server ChannelBinding: %v", err)
+	}
+	otherBinding, err := ChannelBinding(otherClient)
+	if err != nil {
+		t.Fatalf("other ChannelBinding: %v", err)
+	}
+
+	if len(clientBinding) != 32 {
+		t.Errorf("binding is %d bytes, want 32", len(clientBinding))
+	}
+	if !bytes.Equal(clientBinding, serverBinding) {
+		t.Error("both ends of a connection derived different bindings")
+	}
+	if bytes.Equal(clientBinding, otherBinding) {
+		t.Error("two connections derived the same binding")
+	}
+
+	if _, err := ChannelBinding(context.Background()); err == nil {
+		t.Error("ChannelBinding without a peer succeeded")
+	}
+	insecure := peer.NewContext(context.Background(), &peer.Peer{})
+	if _, err := ChannelBinding(insecure); err == nil {
+		t.Error("ChannelBinding without TLS succeeded")
+	}
+}
+
+func TestVerifyBoundChallenge(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := &AuthorizedKey{Key: signer.PublicKey(), Command: "uptime", NoPTY: true}
+	authenticator := &PublicKeyAuthenticator{}
+
+	nonce, err := NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	binding := bytes.Repeat([]byte{1}, 32)
+	signature, err := signer.Sign(rand.Reader, ChallengeData("alice", nonce, binding))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, err := authenticator.Verify(entry, "alice", nonce, binding, signature)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if identity.Name != "alice" || identity.Method != "publickey" || identity.ForcedCommand != "uptime" || !identity.NoPTY {
+		t.Errorf("identity = %+v", identity)
+	}
+
+	otherNonce, err := NewNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name    string
+		user    string
+		nonce   []byte
+		binding []byte
+	}{
+		{"other connection", "alice", nonce, bytes.Repeat([]byte{2}, 32)},
+		{"no connection", "alice", nonce, nil},
+		{"other user", "bob", nonce, binding},
+		{"other nonce", "alice", otherNonce, binding},
+	} {
+		if _, err := authenticator.Verify(entry, test.user, test.nonce, test.binding, signature); err == nil {
+			t.Errorf("%s: signature was accepted", test.name)
+		}
+	}
+}
+
+func TestNewPublicKeyAuthenticator(t *testing.T) {
+	for _, test := range []struct {
+		keysFile string
+		valid    bool
+	}{
+		{"/etc/gssh/authorized_keys", false},
+		{"", false},
+		{"/etc/gssh/%u.keys", true},
+		{"%h/.ssh/authorized_keys", true},
+	} {
+		_, err := NewPublicKeyAuthenticator(test.keysFile)
+		if (err == nil) != test.valid {
+			t.Errorf("NewPublicKeyAuthenticator(%q) = %v, want valid %v", test.keysFile, err, test.valid)
+		}
+	}
+}
+
+func TestAuthorizeOtherUser(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "alice.keys"), ssh.MarshalAuthorizedKey(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bob.keys"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := NewPublicKeyAuthenticator(filepath.Join(dir, "%u.keys"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000}
+	if _, err := authenticator.Authorize("alice", key, addr); err != nil {
+		t.Errorf("alice's key as alice: %v", err)
+	}
+	for _, user := range []string{"bob", "carol", "../alice.keys"} {
+		if _, err := authenticator.Authorize(user, key, addr); err == nil {
+			t.Errorf("alice's key was accepted for %q", user)
+		}
+	}
+}
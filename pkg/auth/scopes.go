@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Scope limits what an identity may do
+type Scope string
+
+const (
+	ScopeExec    Scope = "exec"    // Run commands with Exec
+	ScopeObserve Scope = "observe" // List sessions and attach to them read-only
+	ScopeShell   Scope = "shell"   // Open, attach to and signal interactive sessions
+	ScopeAdmin   Scope = "admin"   // Everything
+)
+
+var knownScopes = map[Scope]bool{ScopeExec: true, ScopeObserve: true, ScopeShell: true, ScopeAdmin: true}
+
+// ParseScopes parses a comma separated list of scopes
+func ParseScopes(list string) ([]Scope, error) {
+	var scopes []Scope
+	for _, name := range strings.Split(list, ",") {
+		scope := Scope(strings.TrimSpace(name))
+		if !knownScopes[scope] {
+			return nil, fmt.Errorf("unknown scope: %q", scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// HasScope reports whether the identity may act within scope. Identities
+// without scopes, like users who logged in with a key or a certificate, have
+// every scope but admin.
+func (i *Identity) HasScope(scope Scope) bool {
+	if i.Scopes == nil {
+		return scope != ScopeAdmin
+	}
+	for _, s := range i.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckScope fails with PermissionDenied unless the identity of ctx has one
+// of the scopes. Anonymous requests are let through, they are only possible
+// when authentication is optional.
+func CheckScope(ctx context.Context, scopes ...Scope) error {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, scope := range scopes {
+		if identity.HasScope(scope) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "%s is not allowed to do this", identity.Name)
+}
+
+// ScopeInterceptors build the interceptors that check the identity of each
+// request has one of the scopes of its method. Methods missing from
+// methodScopes need the admin scope. They must run after Interceptors.
+func ScopeInterceptors(methodScopes map[string][]Scope) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	check := func(ctx context.Context, method string) error {
+		scopes, ok := methodScopes[method]
+		if !ok {
+			scopes = []Scope{ScopeAdmin}
+		}
+		return CheckScope(ctx, scopes...)
+	}
+
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
+
+// IsAdmin reports whether the identity of ctx has the admin scope
+func IsAdmin(ctx context.Context) bool {
+	identity, ok := FromContext(ctx)
+	return ok && identity.HasScope(ScopeAdmin)
+}
+
+// WithAdmins grants the admin scope to the listed users when authenticator
+// identifies them without scopes of their own
+func WithAdmins(admins []string, authenticator Authenticator) Authenticator {
+	isAdmin := make(map[string]bool, len(admins))
+	for _, name := range admins {
+		isAdmin[name] = true
+	}
+
+	return func(ctx context.Context) (*Identity, error) {
+		identity, err := authenticator(ctx)
+		if err != nil || identity == nil || identity.Scopes != nil || !isAdmin[identity.Name] {
+			return identity, err
+		}
+
+		admin := *identity
+		admin.Scopes = []Scope{ScopeAdmin}
+		return &admin, nil
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// SessionTokenHeader is the metadata key clients send their session token in
+const SessionTokenHeader = "gssh-session-token"
+
+// SessionTokens keeps the tokens issued to users after they authenticated
+type SessionTokens struct {
+	ttl    time.Duration
+	mux    sync.Mutex
+	tokens map[string]sessionToken
+}
+
+type sessionToken struct {
+	identity  *Identity
+	expiresAt time.Time
+}
+
+func NewSessionTokens(ttl time.Duration) *SessionTokens {
+	return &SessionTokens{ttl: ttl, tokens: make(map[string]sessionToken)}
+}
+
+// Issue creates a token that authenticates as identity until it expires
+func (t *SessionTokens) Issue(identity *Identity) (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(raw)
+	expiresAt := time.Now().Add(t.ttl)
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	// Drop expired tokens while we are here
+	for key, entry := range t.tokens {
+		if time.Now().After(entry.expiresAt) {
+			delete(t.tokens, key)
+		}
+	}
+	t.tokens[token] = sessionToken{identity: identity, expiresAt: expiresAt}
+
+	return token, expiresAt, nil
+}
+
+// Lookup returns the identity of a valid token
+func (t *SessionTokens) Lookup(token string) (*Identity, bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	entry, ok := t.tokens[token]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(t.tokens, token)
+		return nil, false
+	}
+	return entry.identity, true
+}
+
+// Authenticator identifies clients by the session token in their metadata
+func (t *SessionTokens) Authenticator() Authenticator {
+	return func(ctx context.Context) (*Identity, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok || len(md.Get(SessionTokenHeader)) == 0 {
+			return nil, nil
+		}
+
+		identity, ok := t.Lookup(md.Get(SessionTokenHeader)[0])
+		if !ok {
+			return nil, fmt.Errorf("invalid or expired session token")
+		}
+		return identity, nil
+	}
+}
@@ -0,0 +1,111 @@
+package pki
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// TrustBundle is what a client needs to trust a server it never talked to:
+// the CA chain, or the server certificate when there is no CA, with the
+// fingerprint and validity of the first certificate
+type TrustBundle struct {
+	Certificates string    `json:"certificates"` // PEM
+	Fingerprint  string    `json:"fingerprint"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+}
+
+// SignedBundle carries the bundle as it was signed, so the signature can be
+// checked against the exact bytes
+type SignedBundle struct {
+	Bundle    json.RawMessage `json:"bundle"`
+	Signature string          `json:"signature,omitempty"` // HMAC-SHA256 with the pre-shared key
+}
+
+// Fingerprint identifies a certificate the way ssh shows host keys
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// NewTrustBundle describes the PEM certificates a client should trust
+func NewTrustBundle(certificates []byte) (*TrustBundle, error) {
+	cert, err := firstCertificate(certificates)
+	if err != nil {
+		return nil, err
+	}
+	return &TrustBundle{
+		Certificates: string(certificates),
+		Fingerprint:  Fingerprint(cert.Raw),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+	}, nil
+}
+
+// Sign signs the bundle with the pre-shared key, or leaves it unsigned when
+// there is none
+func (b *TrustBundle) Sign(psk []byte) (*SignedBundle, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := &SignedBundle{Bundle: data}
+	if len(psk) > 0 {
+		signed.Signature = base64.StdEncoding.EncodeToString(bundleMAC(data, psk))
+	}
+	return signed, nil
+}
+
+// Verify checks the signature with the pre-shared key and opens the bundle
+func (s *SignedBundle) Verify(psk []byte) (*TrustBundle, error) {
+	signature, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil || s.Signature == "" {
+		return nil, fmt.Errorf("trust bundle is not signed")
+	}
+	if !hmac.Equal(signature, bundleMAC(s.Bundle, psk)) {
+		return nil, fmt.Errorf("trust bundle signature doesn't match the pre-shared key")
+	}
+	return s.Open()
+}
+
+// Open parses the bundle without checking its signature, and checks it is
+// consistent and currently valid
+func (s *SignedBundle) Open() (*TrustBundle, error) {
+	var bundle TrustBundle
+	if err := json.Unmarshal(s.Bundle, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid trust bundle: %v", err)
+	}
+
+	cert, err := firstCertificate([]byte(bundle.Certificates))
+	if err != nil {
+		return nil, err
+	}
+	if Fingerprint(cert.Raw) != bundle.Fingerprint {
+		return nil, fmt.Errorf("trust bundle fingerprint doesn't match its certificate")
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("trust bundle certificate is not valid now, valid from %s to %s", cert.NotBefore, cert.NotAfter)
+	}
+	return &bundle, nil
+}
+
+func bundleMAC(data, psk []byte) []byte {
+	mac := hmac.New(sha256.New, psk)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func firstCertificate(certificates []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certificates)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate in trust bundle")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,104 @@
+package pki
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func newSignedBundle(t *testing.T, psk []byte) (*TrustBundle, *SignedBundle) {
+	t.Helper()
+
+	ca, err := NewCA("gSSH test CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := NewTrustBundle(ca.CertPEM())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := bundle.Sign(psk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bundle, signed
+}
+
+func TestSignedBundleVerify(t *testing.T) {
+	psk := []byte("correct horse battery staple")
+	bundle, signed := newSignedBundle(t, psk)
+	_, unsigned := newSignedBundle(t, nil)
+	other, _ := newSignedBundle(t, psk)
+
+	opened, err := signed.Verify(psk)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if opened.Fingerprint != bundle.Fingerprint || opened.Certificates != bundle.Certificates {
+		t.Errorf("Verify opened %s, want %s", opened.Fingerprint, bundle.Fingerprint)
+	}
+
+	// Swaps in the certificate of another CA while keeping the signature
+	swapped := *signed
+	swapped.Bundle = bytes.Replace(signed.Bundle, []byte(bundle.Fingerprint), []byte(other.Fingerprint), 1)
+
+	flipped := *signed
+	flipped.Bundle = bytes.Clone(signed.Bundle)
+	flipped.Bundle[len(flipped.Bundle)/2] ^= 1
+
+	badSignature := *signed
+	mac, _ := base64.StdEncoding.DecodeString(signed.Signature)
+	mac[0] ^= 1
+	badSignature.Signature = base64.StdEncoding.EncodeToString(mac)
+
+	garbled := *signed
+	garbled.Signature = "not base64!"
+
+	for _, test := range []struct {
+		name   string
+		signed *SignedBundle
+		psk    []byte
+	}{
+		{"wrong pre-shared key", signed, []byte("incorrect horse battery staple")},
+		{"empty pre-shared key", signed, nil},
+		{"tampered fingerprint", &swapped, psk},
+		{"tampered byte", &flipped, psk},
+		{"tampered signature", &badSignature, psk},
+		{"invalid signature encoding", &garbled, psk},
+		{"unsigned bundle", unsigned, psk},
+	} {
+		if _, err := test.signed.Verify(test.psk); err == nil {
+			t.Errorf("%s: Verify succeeded", test.name)
+		}
+	}
+}
+
+func TestSignedBundleOpen(t *testing.T) {
+	bundle, unsigned := newSignedBundle(t, nil)
+	if unsigned.Signature != "" {
+		t.Errorf("bundle signed without a pre-shared key: %q", unsigned.Signature)
+	}
+
+	opened, err := unsigned.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if opened.Fingerprint != bundle.Fingerprint {
+		t.Errorf("Open fingerprint = %s, want %s", opened.Fingerprint, bundle.Fingerprint)
+	}
+
+	// The fingerprint shown to the user has to be the one of the certificate
+	other, _ := newSignedBundle(t, nil)
+	mismatched := *unsigned
+	mismatched.Bundle = bytes.Replace(unsigned.Bundle, []byte(bundle.Fingerprint), []byte(other.Fingerprint), 1)
+	if _, err := mismatched.Open(); err == nil {
+		t.Error("Open accepted a fingerprint of another certificate")
+	}
+
+	if _, err := (&SignedBundle{Bundle: []byte("{")}).Open(); err == nil {
+		t.Error("Open accepted invalid JSON")
+	}
+	if _, err := (&SignedBundle{Bundle: []byte(`{"certificates":""}`)}).Open(); err == nil {
+		t.Error("Open accepted a bundle without certificates")
+	}
+}
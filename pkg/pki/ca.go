@@ -0,0 +1,204 @@
+// Package pki is the small certificate authority gSSH uses to issue its own
+// server and client certificates, so no openssl is needed to set it up.
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	CAValidity     = 10 * 365 * 24 * time.Hour
+	ServerValidity = 90 * 24 * time.Hour
+	ClientValidity = 365 * 24 * time.Hour
+)
+
+// CA signs certificates with its key
+type CA struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// NewCA creates a self-signed certificate authority
+func NewCA(commonName string) (*CA, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newTemplate(pkix.Name{CommonName: commonName}, CAValidity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.MaxPathLenZero = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// LoadCA reads a CA certificate and its key from PEM files
+func LoadCA(certFile, keyFile string) (*CA, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA: %v", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("%s is not a CA certificate", certFile)
+	}
+
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA key in %s", keyFile)
+	}
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// Save writes the CA certificate and key as PEM files
+func (ca *CA) Save(certFile, keyFile string) error {
+	return writePair(certFile, keyFile, [][]byte{ca.Cert.Raw}, ca.Key)
+}
+
+// CertPEM is the CA certificate clients trust
+func (ca *CA) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Cert.Raw})
+}
+
+// IssueServer creates a server certificate for the host names and IP
+// addresses in hosts. The certificate chain includes the CA, so clients that
+// pinned the CA can verify renewed certificates.
+func (ca *CA) IssueServer(hosts []string) (*tls.Certificate, error) {
+	template, err := newTemplate(pkix.Name{CommonName: hosts[0]}, ServerValidity)
+	if err != nil {
+		return nil, err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	return ca.issue(template)
+}
+
+// IssueClient creates a client certificate for mutual TLS. The name is the
+// subject common name, which becomes the gSSH identity of the client.
+func (ca *CA) IssueClient(name string) (*tls.Certificate, error) {
+	template, err := newTemplate(pkix.Name{CommonName: name}, ClientValidity)
+	if err != nil {
+		return nil, err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	return ca.issue(template)
+}
+
+func (ca *CA) issue(template *x509.Certificate) (*tls.Certificate, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der, ca.Cert.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// SaveCertificate writes the certificate chain and key of cert as PEM files
+func SaveCertificate(cert *tls.Certificate, certFile, keyFile string) error {
+	key, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key")
+	}
+	return writePair(certFile, keyFile, cert.Certificate, key)
+}
+
+func newKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+func newTemplate(subject pkix.Name, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	// Backdated a little, so clocks slightly behind still accept it
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+// writePair writes the certificates and the key, the key readable only by
+// its owner. Both are written to temporary files first and renamed, so a
+// server reloading them never sees half a pair.
+func writePair(certFile, keyFile string, chain [][]byte, key crypto.Signer) error {
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	if err := writeFile(keyFile, keyPEM, 0o600); err != nil {
+		return err
+	}
+	return writeFile(certFile, certPEM, 0o644)
+}
+
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,145 @@
+package pki
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// renewCheckInterval is how often the certificate is checked for expiry
+const renewCheckInterval = time.Hour
+
+// Renewer serves the server certificate through tls.Config.GetCertificate,
+// so it can be replaced without a restart. It reloads the files when they
+// change on disk, and reissues the certificate with the CA before it expires.
+type Renewer struct {
+	CertFile    string
+	KeyFile     string
+	CA          *CA           // Renews the certificate, nil to only reload it
+	RenewBefore time.Duration // How long before expiry to renew
+
+	mux     sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// Load reads the certificate from its files
+func (r *Renewer) Load() error {
+	info, err := os.Stat(r.CertFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.cert = &cert
+	r.modTime = info.ModTime()
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *Renewer) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.cert, nil
+}
+
+// Run checks the certificate until ctx is done
+func (r *Renewer) Run(ctx context.Context) {
+	ticker := time.NewTicker(renewCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.check(); err != nil {
+			slog.Error("failed to renew server certificate", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Renewer) check() error {
+	// Pick up certificates replaced by hand or by another tool
+	if info, err := os.Stat(r.CertFile); err == nil {
+		r.mux.RLock()
+		changed := !info.ModTime().Equal(r.modTime)
+		r.mux.RUnlock()
+		if changed {
+			if err := r.Load(); err != nil {
+				return err
+			}
+			slog.Info("reloaded server certificate", "file", r.CertFile)
+		}
+	}
+
+	leaf, err := r.Leaf()
+	if err != nil {
+		return err
+	}
+	if r.CA == nil || time.Until(leaf.NotAfter) > r.RenewBefore {
+		return nil
+	}
+
+	// Keep the names of the certificate being replaced
+	hosts := append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		hosts = append(hosts, ip.String())
+	}
+	if len(hosts) == 0 {
+		hosts = []string{leaf.Subject.CommonName}
+	}
+
+	cert, err := r.CA.IssueServer(hosts)
+	if err != nil {
+		return err
+	}
+	if err := SaveCertificate(cert, r.CertFile, r.KeyFile); err != nil {
+		return err
+	}
+	if err := r.Load(); err != nil {
+		return err
+	}
+	slog.Info("renewed server certificate", "notAfter", cert.Leaf.NotAfter)
+	return nil
+}
+
+// Leaf parses the certificate currently served
+func (r *Renewer) Leaf() (*x509.Certificate, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return x509.ParseCertificate(r.cert.Certificate[0])
+}
+
+// DefaultHosts are the names a server certificate is issued for when none
+// are given: localhost, the host name and the addresses of the machine
+func DefaultHosts() []string {
+	hosts := []string{"localhost"}
+	if hostname, err := os.Hostname(); err == nil && hostname != "localhost" {
+		hosts = append(hosts, hostname)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return append(hosts, "127.0.0.1", "::1")
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLinkLocalUnicast() {
+			hosts = append(hosts, ipNet.IP.String())
+		}
+	}
+	return hosts
+}
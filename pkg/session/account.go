@@ -0,0 +1,146 @@
+package session
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Account is the local Unix user a shell runs as
+type Account struct {
+	Name   string
+	Uid    uint32
+	Gid    uint32
+	Groups []uint32
+	Home   string
+	Shell  string // Login shell from /etc/passwd
+}
+
+// LookupAccount finds the local account of a user with its supplementary groups
+func LookupAccount(name string) (*Account, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid of %s: %v", name, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid of %s: %v", name, err)
+	}
+
+	groupIds, err := u.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups of %s: %v", name, err)
+	}
+	groups := make([]uint32, 0, len(groupIds))
+	for _, id := range groupIds {
+		group, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid group of %s: %v", name, err)
+		}
+		groups = append(groups, uint32(group))
+	}
+
+	return &Account{
+		Name:   u.Username,
+		Uid:    uint32(uid),
+		Gid:    uint32(gid),
+		Groups: groups,
+		Home:   u.HomeDir,
+		Shell:  loginShell(u.Username),
+	}, nil
+}
+
+// loginShell reads the shell of the user from /etc/passwd, which os/user
+// doesn't expose. Like login, it falls back to /bin/sh.
+func loginShell(name string) string {
+	passwd, err := os.Open("/etc/passwd")
+	if err != nil {
+		return "/bin/sh"
+	}
+	defer passwd.Close()
+
+	scanner := bufio.NewScanner(passwd)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) == 7 && fields[0] == name && fields[6] != "" {
+			return fields[6]
+		}
+	}
+	return "/bin/sh"
+}
+
+// Environ is the clean environment of a fresh login of the account
+func (a *Account) Environ() []string {
+	path := "/usr/local/bin:/usr/bin:/bin"
+	if a.Uid == 0 {
+		path = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+	}
+
+	term := os.Getenv("TERM")
+	if term == "" {
+		term = "xterm-256color"
+	}
+
+	return []string{
+		"HOME=" + a.Home,
+		"USER=" + a.Name,
+		"LOGNAME=" + a.Name,
+		"SHELL=" + a.Shell,
+		"PATH=" + path,
+		"TERM=" + term,
+		"MAIL=/var/mail/" + a.Name,
+	}
+}
+
+// Apply makes cmd run as the account, from its home directory and with its
+// login environment
+func (a *Account) Apply(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = &syscall.Credential{Uid: a.Uid, Gid: a.Gid, Groups: a.Groups}
+	cmd.Dir = a.Home
+	cmd.Env = a.Environ()
+}
+
+// Command runs command through the login shell of the account, or starts the
+// shell as an interactive login shell when command is empty
+func (a *Account) Command(command string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if command != "" {
+		cmd = exec.Command(a.Shell, "-c", command)
+	} else {
+		// A leading dash in argv[0] tells the shell it is a login shell
+		cmd = exec.Command(a.Shell)
+		cmd.Args[0] = "-" + filepath.Base(a.Shell)
+	}
+	a.Apply(cmd)
+	return cmd
+}
+
+// ownTerminal gives the account the terminal, readable by it and writable by
+// the tty group, as login and sshd do
+func (a *Account) ownTerminal(tty *os.File) error {
+	gid := int(a.Gid)
+	if group, err := user.LookupGroup("tty"); err == nil {
+		if ttyGid, err := strconv.Atoi(group.Gid); err == nil {
+			gid = ttyGid
+		}
+	}
+
+	if err := tty.Chown(int(a.Uid), gid); err != nil {
+		return fmt.Errorf("failed to hand the terminal to %s: %v", a.Name, err)
+	}
+	return tty.Chmod(0o620)
+}
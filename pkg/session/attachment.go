@@ -0,0 +1,159 @@
+package session
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Mode tells what an attached client may do with a session
+type Mode int
+
+const (
+	Writer   Mode = iota // Sends input to the session and watches its output
+	Observer             // Only watches the output
+)
+
+var ErrWriterAttached = errors.New("session already has a writer attached")
+
+// Attachment is a client receiving the output of a session
+type Attachment struct {
+	ClientAddress string
+	Identity      string // Authenticated user, empty for anonymous clients
+	Mode          Mode
+	AttachedAt    time.Time
+
+	session *BashSession
+	output  chan []byte
+	notices chan string
+	dropped chan struct{}
+	once    sync.Once
+}
+
+// outputBuffer is how many chunks of output may queue up for a client before
+// it is dropped for not keeping up
+const outputBuffer = 256
+
+// Output delivers the live output of the session. It is closed once the
+// shell stops producing output.
+func (a *Attachment) Output() <-chan []byte {
+	return a.output
+}
+
+// Dropped is closed when the client fell too far behind the output and was
+// detached, so it can't hold back the session. It may reattach and get the
+// scrollback.
+func (a *Attachment) Dropped() <-chan struct{} {
+	return a.dropped
+}
+
+// Notices delivers the notices sent to the session's clients with Warn
+func (a *Attachment) Notices() <-chan string {
+	return a.notices
+}
+
+// Detach stops forwarding output to the client, the session keeps running
+func (a *Attachment) Detach() {
+	a.once.Do(func() {
+		s := a.session
+		s.outputMux.Lock()
+		defer s.outputMux.Unlock()
+		for i, attachment := range s.attachments {
+			if attachment == a {
+				s.attachments = append(s.attachments[:i], s.attachments[i+1:]...)
+				break
+			}
+		}
+	})
+}
+
+// Attach starts forwarding the session output to a new client. It returns the
+// scrollback to replay before the live output. Only one writer can be attached
+// at a time unless the session allows shared writes.
+func (s *BashSession) Attach(clientAddress, identity string, mode Mode) (*Attachment, []byte, error) {
+	s.outputMux.Lock()
+	defer s.outputMux.Unlock()
+
+	if mode == Writer && !s.SharedWrite && s.hasWriter() {
+		return nil, nil, ErrWriterAttached
+	}
+
+	a := &Attachment{
+		ClientAddress: clientAddress,
+		Identity:      identity,
+		Mode:          mode,
+		AttachedAt:    time.Now(),
+		session:       s,
+		output:        make(chan []byte, outputBuffer),
+		notices:       make(chan string, 1),
+		dropped:       make(chan struct{}),
+	}
+
+	if s.outputDone {
+		close(a.output)
+	} else {
+		s.attachments = append(s.attachments, a)
+	}
+	return a, s.scrollback.Bytes(), nil
+}
+
+// Attachments returns the clients currently attached to the session
+func (s *BashSession) Attachments() []*Attachment {
+	s.outputMux.Lock()
+	defer s.outputMux.Unlock()
+	return append([]*Attachment(nil), s.attachments...)
+}
+
+// InUse tells whether any client is attached to the session
+func (s *BashSession) InUse() bool {
+	s.outputMux.Lock()
+	defer s.outputMux.Unlock()
+	return len(s.attachments) > 0
+}
+
+// CanWrite tells whether a new writer would be accepted by Attach
+func (s *BashSession) CanWrite() bool {
+	s.outputMux.Lock()
+	defer s.outputMux.Unlock()
+	return s.SharedWrite || !s.hasWriter()
+}
+
+func (s *BashSession) hasWriter() bool {
+	for _, a := range s.attachments {
+		if a.Mode == Writer {
+			return true
+		}
+	}
+	return false
+}
+
+// broadcast forwards a chunk of output to every attached client without ever
+// blocking. A client whose queue is full stopped reading, e.g. a suspended
+// client or one piped into a pager, and is dropped so it can't hold back the
+// shell, the other clients or anyone waiting on the session.
+func (s *BashSession) broadcast(data []byte) {
+	attachments := s.attachments[:0]
+	for _, a := range s.attachments {
+		select {
+		case a.output <- append([]byte(nil), data...):
+			attachments = append(attachments, a)
+		default:
+			close(a.dropped)
+		}
+	}
+	clear(s.attachments[len(attachments):])
+	s.attachments = attachments
+}
+
+// Warn queues a notice for every attached client. It never blocks, and a
+// notice is dropped for clients that didn't receive the previous one yet.
+func (s *BashSession) Warn(notice string) {
+	s.outputMux.Lock()
+	defer s.outputMux.Unlock()
+	for _, a := range s.attachments {
+		select {
+		case a.notices <- notice:
+		default:
+		}
+	}
+}
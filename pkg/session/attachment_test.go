@@ -0,0 +1,124 @@
+package session
+
+import (
+	"fmt"
+	"testing"
+)
+
+// newTestSession is a session without a shell, fed through record
+func newTestSession(sharedWrite bool) *BashSession {
+	return &BashSession{
+		SharedWrite: sharedWrite,
+		exited:      make(chan struct{}),
+		scrollback:  newRingBuffer(64),
+	}
+}
+
+func TestAttachWriters(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		sharedWrite bool
+		attached    []Mode
+		mode        Mode
+		allowed     bool
+	}{
+		{"first writer", false, nil, Writer, true},
+		{"second writer", false, []Mode{Writer}, Writer, false},
+		{"second writer with shared writes", true, []Mode{Writer}, Writer, true},
+		{"observer next to a writer", false, []Mode{Writer}, Observer, true},
+		{"writer next to observers", false, []Mode{Observer, Observer}, Writer, true},
+	} {
+		bashSession := newTestSession(test.sharedWrite)
+		for _, mode := range test.attached {
+			if _, _, err := bashSession.Attach("client", "", mode); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+		}
+
+		if got := bashSession.CanWrite(); test.mode == Writer && got != test.allowed {
+			t.Errorf("%s: CanWrite() = %v, want %v", test.name, got, test.allowed)
+		}
+		_, _, err := bashSession.Attach("client", "", test.mode)
+		if test.allowed && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if !test.allowed && err != ErrWriterAttached {
+			t.Errorf("%s: got %v, want %v", test.name, err, ErrWriterAttached)
+		}
+	}
+}
+
+func TestAttachReplaysScrollback(t *testing.T) {
+	bashSession := newTestSession(false)
+	bashSession.record([]byte("before "))
+
+	a, scrollback, err := bashSession.Attach("client", "", Observer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(scrollback) != "before " {
+		t.Errorf("scrollback = %q, want %q", scrollback, "before ")
+	}
+
+	bashSession.record([]byte("after"))
+	if got := string(<-a.Output()); got != "after" {
+		t.Errorf("live output = %q, want %q", got, "after")
+	}
+}
+
+// TestSlowClientDropped checks that a client that stops reading is dropped
+// once its queue is full, without holding back the others
+func TestSlowClientDropped(t *testing.T) {
+	bashSession := newTestSession(false)
+	slow, _, err := bashSession.Attach("slow", "", Observer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, _, err := bashSession.Attach("fast", "", Writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range outputBuffer {
+		bashSession.record([]byte(fmt.Sprint(i)))
+		<-fast.Output()
+	}
+	select {
+	case <-slow.Dropped():
+		t.Fatal("client dropped with a queue that isn't full yet")
+	default:
+	}
+
+	bashSession.record([]byte("one too many"))
+	select {
+	case <-slow.Dropped():
+	default:
+		t.Fatal("client with a full queue wasn't dropped")
+	}
+	if got := string(<-fast.Output()); got != "one too many" {
+		t.Errorf("output of the other client = %q, want %q", got, "one too many")
+	}
+	if attachments := bashSession.Attachments(); len(attachments) != 1 || attachments[0] != fast {
+		t.Errorf("attachments = %v, want only the client keeping up", attachments)
+	}
+	if len(slow.Output()) != outputBuffer {
+		t.Errorf("dropped client has %d chunks queued, want the %d it had", len(slow.Output()), outputBuffer)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	bashSession := newTestSession(false)
+	a, _, err := bashSession.Attach("client", "", Writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Detach()
+	a.Detach()
+	if bashSession.InUse() {
+		t.Error("session still in use after its only client detached")
+	}
+	if _, _, err := bashSession.Attach("client", "", Writer); err != nil {
+		t.Errorf("writer refused after the previous one detached: %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// CastHeader is the first line of an asciicast v2 recording
+type CastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event types of asciicast v2
+const (
+	CastOutput = "o"
+	CastInput  = "i"
+	CastResize = "r"
+)
+
+// Recording writes the output, input and resize events of a session as an
+// asciicast v2 file, one JSON array per event with the seconds elapsed since
+// the recording started
+type Recording struct {
+	mux     sync.Mutex
+	file    *os.File
+	path    string
+	started time.Time
+	err     error // First write error, the recording stops after it
+
+	// Bytes of an incomplete UTF-8 sequence at the end of the last chunk,
+	// completed by the next one since events must be valid strings
+	pending map[string][]byte
+}
+
+// RecordingPath is where a session is recorded, grouped by user with the
+// start time in the name, so replacing a session doesn't overwrite it
+func RecordingPath(dir, user, sessionId string, started time.Time) string {
+	name := fmt.Sprintf("%s-%s.cast", pathSafe(sessionId), started.UTC().Format("20060102T150405Z"))
+	return filepath.Join(dir, pathSafe(user), name)
+}
+
+// pathSafe keeps client supplied names inside their directory
+func pathSafe(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_", "\x00", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return "_" + name
+	}
+	return name
+}
+
+// NewRecording creates the recording file, and its directory, and writes the
+// header. Recordings may hold secrets, so only the server's user can read them.
+func NewRecording(path string, header CastHeader) (*Recording, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	header.Version = 2
+	header.Timestamp = started.Unix()
+	line, err := json.Marshal(header)
+	if err == nil {
+		_, err = file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &Recording{
+		file:    file,
+		path:    path,
+		started: started,
+		pending: make(map[string][]byte),
+	}, nil
+}
+
+// Path is the file the session is recorded to
+func (r *Recording) Path() string {
+	return r.path
+}
+
+// Output records a chunk of output of the PTY
+func (r *Recording) Output(data []byte) error {
+	return r.event(CastOutput, data)
+}
+
+// Input records a chunk of input written to the PTY
+func (r *Recording) Input(data []byte) error {
+	return r.event(CastInput, data)
+}
+
+// Resize records a new window size
+func (r *Recording) Resize(cols, rows uint16) error {
+	return r.event(CastResize, []byte(fmt.Sprintf("%dx%d", cols, rows)))
+}
+
+func (r *Recording) event(kind string, data []byte) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	data = append(r.pending[kind], data...)
+	cut := incompleteSuffix(data)
+	r.pending[kind] = append([]byte(nil), data[len(data)-cut:]...)
+	data = data[:len(data)-cut]
+	if len(data) == 0 {
+		return nil
+	}
+
+	line, err := json.Marshal([]any{r.elapsed(), kind, strings.ToValidUTF8(string(data), string(utf8.RuneError))})
+	if err != nil {
+		r.err = err
+		return err
+	}
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		r.err = err
+		return err
+	}
+	return nil
+}
+
+// elapsed is the time since the recording started, in seconds with
+// microsecond precision
+func (r *Recording) elapsed() float64 {
+	return math.Round(time.Since(r.started).Seconds()*1e6) / 1e6
+}
+
+// incompleteSuffix is the length of a UTF-8 sequence cut off at the end of data
+func incompleteSuffix(data []byte) int {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		c := data[len(data)-i]
+		if utf8.RuneStart(c) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return i
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// Close flushes what is left of incomplete sequences and closes the file
+func (r *Recording) Close() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.pending == nil {
+		return nil
+	}
+	for kind, rest := range r.pending {
+		if len(rest) > 0 && r.err == nil {
+			line, _ := json.Marshal([]any{r.elapsed(), kind, strings.ToValidUTF8(string(rest), string(utf8.RuneError))})
+			_, r.err = r.file.Write(append(line, '\n'))
+		}
+	}
+	r.pending = nil
+	if r.err == nil {
+		r.err = os.ErrClosed
+	}
+	return r.file.Close()
+}
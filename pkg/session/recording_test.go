@@ -0,0 +1,134 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIncompleteSuffix(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ASCII", "abc", 0},
+		{"complete two bytes", "aé", 0},
+		{"first of two bytes", "a\xc3", 1},
+		{"two of three bytes", "a\xe2\x82", 2},
+		{"complete three bytes", "a€", 0},
+		{"three of four bytes", "\xf0\x9f\x98", 3},
+		{"complete four bytes", "😀", 0},
+		{"stray continuation byte", "a\x82", 0},
+	} {
+		if got := incompleteSuffix([]byte(test.data)); got != test.want {
+			t.Errorf("%s: incompleteSuffix(%q) = %d, want %d", test.name, test.data, got, test.want)
+		}
+	}
+}
+
+func TestRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alice", "session.cast")
+	recording, err := NewRecording(path, CastHeader{Width: 80, Height: 24, Title: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "é" is split across two chunks of output, with input in between
+	for _, write := range []func() error{
+		func() error { return recording.Output([]byte("$ ")) },
+		func() error { return recording.Input([]byte("ls\r")) },
+		func() error { return recording.Output([]byte("caf\xc3")) },
+		func() error { return recording.Input([]byte("x")) },
+		func() error { return recording.Output([]byte("\xa9\r\n")) },
+		func() error { return recording.Resize(100, 30) },
+		func() error { return recording.Output([]byte("\xe2\x82")) },
+	} {
+		if err := write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recording.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := recording.Output([]byte("late")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Output after Close = %v, want %v", err, os.ErrClosed)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("recording mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+
+	if !scanner.Scan() {
+		t.Fatal("no header")
+	}
+	var header CastHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		t.Fatalf("header %s: %v", scanner.Text(), err)
+	}
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 || header.Title != "session" || header.Timestamp == 0 {
+		t.Errorf("header = %+v", header)
+	}
+
+	want := [][2]string{
+		{CastOutput, "$ "},
+		{CastInput, "ls\r"},
+		{CastOutput, "caf"},
+		{CastInput, "x"},
+		{CastOutput, "é\r\n"},
+		{CastResize, "100x30"},
+		{CastOutput, "�"}, // The incomplete end is flushed on Close
+	}
+	var last float64
+	for i := 0; scanner.Scan(); i++ {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("event %s: %v", scanner.Text(), err)
+		}
+		if i >= len(want) {
+			t.Fatalf("unexpected event %s", scanner.Text())
+		}
+		elapsed, ok := event[0].(float64)
+		if len(event) != 3 || !ok || elapsed < last {
+			t.Errorf("event %d = %v, want [<elapsed since the previous one> %q %q]", i, event, want[i][0], want[i][1])
+			continue
+		}
+		last = elapsed
+		if event[1] != want[i][0] || event[2] != want[i][1] {
+			t.Errorf("event %d = %q %q, want %q %q", i, event[1], event[2], want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestRecordingPath(t *testing.T) {
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, test := range []struct {
+		user, sessionId string
+		want            string
+	}{
+		{"alice", "build", "rec/alice/build-20260102T030405Z.cast"},
+		{"", "build", "rec/_/build-20260102T030405Z.cast"},
+		{"..", "../../etc/passwd", "rec/_../.._.._etc_passwd-20260102T030405Z.cast"},
+		{"alice", "a\\b", "rec/alice/a_b-20260102T030405Z.cast"},
+	} {
+		if got := RecordingPath("rec", test.user, test.sessionId, started); got != test.want {
+			t.Errorf("RecordingPath(%q, %q) = %q, want %q", test.user, test.sessionId, got, test.want)
+		}
+	}
+}
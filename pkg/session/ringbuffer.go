@@ -0,0 +1,45 @@
+package session
+
+// ringBuffer keeps the last bytes written to it, dropping the oldest ones
+// once its capacity is reached
+type ringBuffer struct {
+	data  []byte
+	start int // Index of the oldest byte
+	size  int // Number of bytes stored
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{data: make([]byte, capacity)}
+}
+
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	capacity := len(r.data)
+	if capacity == 0 {
+		return n, nil
+	}
+
+	// Only the tail of a write bigger than the buffer is kept
+	if len(p) > capacity {
+		p = p[len(p)-capacity:]
+	}
+
+	end := (r.start + r.size) % capacity
+	copied := copy(r.data[end:], p)
+	copy(r.data, p[copied:])
+
+	r.size += len(p)
+	if r.size > capacity {
+		r.start = (r.start + r.size - capacity) % capacity
+		r.size = capacity
+	}
+	return n, nil
+}
+
+// Bytes returns a copy of the stored bytes, oldest first
+func (r *ringBuffer) Bytes() []byte {
+	out := make([]byte, r.size)
+	copied := copy(out, r.data[r.start:min(r.start+r.size, len(r.data))])
+	copy(out[copied:], r.data[:r.size-copied])
+	return out
+}
@@ -0,0 +1,33 @@
+package session
+
+import "testing"
+
+func TestRingBuffer(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		capacity int
+		writes   []string
+		want     string
+	}{
+		{"empty", 8, nil, ""},
+		{"partly filled", 8, []string{"abc", "de"}, "abcde"},
+		{"exactly full", 8, []string{"abcd", "efgh"}, "abcdefgh"},
+		{"wraps around", 8, []string{"abcdef", "ghij"}, "cdefghij"},
+		{"wraps around twice", 4, []string{"abc", "def", "gh", "i"}, "fghi"},
+		{"write bigger than the buffer", 4, []string{"ab", "cdefghij"}, "ghij"},
+		{"write of the whole capacity after wrapping", 4, []string{"abc", "defg"}, "defg"},
+		{"single bytes", 3, []string{"a", "b", "c", "d", "e"}, "cde"},
+		{"no capacity", 0, []string{"abc"}, ""},
+	} {
+		buffer := newRingBuffer(test.capacity)
+		for _, write := range test.writes {
+			n, err := buffer.Write([]byte(write))
+			if n != len(write) || err != nil {
+				t.Errorf("%s: Write(%q) = %d, %v, want %d, nil", test.name, write, n, err, len(write))
+			}
+		}
+		if got := string(buffer.Bytes()); got != test.want {
+			t.Errorf("%s: Bytes() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
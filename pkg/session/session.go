@@ -1,64 +1,392 @@
 package session
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
-	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/creack/pty"
 	"golang.org/x/sys/unix"
 )
 
+// ScrollbackSize is how many bytes of recent output each session keeps to
+// replay to clients that reattach
+var ScrollbackSize = 64 * 1024
+
+// Bytes written to and read from the PTYs of every session so far
+var bytesIn, bytesOut atomic.Uint64
+
 type BashSession struct {
 	Id              string
 	TerminalCommand *exec.Cmd
 	Ptmx            *os.File
-	InUse           bool
+	CreatedAt       time.Time
+	SharedWrite     bool       // Several writers may be attached at once
+	Command         string     // Runs instead of an interactive bash, if set
+	Owner           string     // Name of the user who created the session, empty if anonymous
+	Viewers         []string   // Other users allowed to observe the session
+	Account         *Account   // Local user the shell runs as, nil for the server's own
+	Recording       *Recording // Where output, input and resizes are recorded, if anywhere
+
+	lastActivity    atomic.Int64 // Unix nanoseconds of the last PTY read or write
+	recordingFailed atomic.Bool
+	exited          chan struct{}
+
+	stateMux sync.Mutex
+	state    State
+
+	// Output is read from the PTY all the time, even with no client attached,
+	// so the shell never blocks on a full PTY and detached output is kept
+	outputMux   sync.Mutex
+	scrollback  *ringBuffer
+	attachments []*Attachment
+	outputDone  bool // The PTY can't be read anymore
+}
+
+// Options changes how the shell of a session is started
+type Options struct {
+	Command string   // Run through "bash -c" instead of an interactive shell
+	Account *Account // Run as this local user with their login shell
+
+	// Record the session to this file, which the session closes once its
+	// output is done
+	Recording *Recording
 }
 
-func (*BashSession) New(sessionId string) (*BashSession, error) {
-	// Initialize a bash session and a PTY session
+func (*BashSession) New(sessionId string, options Options) (*BashSession, error) {
+	// Initialize a bash session and a PTY session. Echo is left enabled,
+	// since the client runs its terminal in raw mode and relies on the PTY
+	// to print back what was typed.
 	bashSession := exec.Command("bash")
-	ptmx, err := pty.Start(bashSession)
+	if options.Command != "" {
+		bashSession = exec.Command("bash", "-c", options.Command)
+	}
+	if options.Account != nil {
+		bashSession = options.Account.Command(options.Command)
+	}
+	ptmx, err := startPTY(bashSession, options.Account)
 	if err != nil {
-		fmt.Printf("Failed to start bash session for %s: %v\n", sessionId, err)
+		slog.Error("failed to start shell", "session", sessionId, "error", err)
+		if options.Recording != nil {
+			_ = options.Recording.Close()
+		}
 		return nil, err
 	}
 
-	// Disable the "echo" from commands
-	var termState *unix.Termios
-	if termState, err = unix.IoctlGetTermios(int(ptmx.Fd()), unix.TCGETS); err != nil {
-		fmt.Printf("Failed to get terminal attributes for %s: %v\n", sessionId, err)
+	session := &BashSession{
+		Id:              sessionId,
+		TerminalCommand: bashSession,
+		Ptmx:            ptmx,
+		CreatedAt:       time.Now(),
+		Command:         options.Command,
+		Account:         options.Account,
+		Recording:       options.Recording,
+		exited:          make(chan struct{}),
+		state:           Running,
+		scrollback:      newRingBuffer(ScrollbackSize),
+	}
+	session.touch()
+
+	go session.pumpOutput()
+
+	// Reap the shell as soon as it exits, so it never lingers as a zombie
+	go func() {
+		_ = bashSession.Wait()
+		session.transition(Exited)
+		close(session.exited)
+	}()
+
+	return session, nil
+}
+
+// startPTY starts cmd on a new PTY. When the shell runs as another account,
+// the terminal is handed over to it first like login does, so programs that
+// reopen it by name still can.
+func startPTY(cmd *exec.Cmd, account *Account) (*os.File, error) {
+	if account == nil {
+		return pty.Start(cmd)
+	}
+
+	ptmx, tty, err := pty.Open()
+	if err != nil {
 		return nil, err
 	}
-	termState.Lflag &^= unix.ECHO
-	if err = unix.IoctlSetTermios(int(ptmx.Fd()), unix.TCSETS, termState); err != nil {
-		fmt.Printf("Failed to set terminal attributes for %s: %v\n", sessionId, err)
+	defer tty.Close()
+
+	if err := account.ownTerminal(tty); err != nil {
+		_ = ptmx.Close()
 		return nil, err
 	}
-	defer func() { _ = unix.IoctlSetTermios(int(ptmx.Fd()), unix.TCSETS, termState) }()
 
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGWINCH)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	if err := cmd.Start(); err != nil {
+		_ = ptmx.Close()
+		return nil, err
+	}
+	return ptmx, nil
+}
 
-	// This ensures that the PTY adjusts to terminal window size changes.
-	go func() {
-		for range ch {
-			if err := pty.InheritSize(os.Stdin, ptmx); err != nil {
-				log.Fatalf("error trying to resize the PTY: %v", err)
-			}
+// pumpOutput reads the PTY until it fails, which happens once the shell
+// exits, keeping the output as scrollback and forwarding it to the attached
+// clients. The session is closed once its output is done and the shell was
+// reaped. A panic only ends the output of this session.
+func (s *BashSession) pumpOutput() {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("session output failed", "session", s.Id, "panic", r)
+			s.finishOutput()
 		}
 	}()
-	ch <- syscall.SIGWINCH
-	defer func() { signal.Stop(ch); close(ch) }()
 
-	return &BashSession{
-		Id:              sessionId,
-		TerminalCommand: bashSession,
-		Ptmx:            ptmx,
-		InUse:           true,
-	}, nil
+	buf := make([]byte, 4096)
+	for {
+		n, err := s.read(buf)
+		if n > 0 {
+			s.record(buf[:n])
+		}
+		if err != nil {
+			// Reading fails with EIO once the shell and its children closed the terminal
+			if s.State() == Running && !errors.Is(err, syscall.EIO) {
+				slog.Warn("failed to read session output", "session", s.Id, "error", err)
+			}
+			s.finishOutput()
+			return
+		}
+	}
+}
+
+// record keeps a chunk of output as scrollback and broadcasts it
+func (s *BashSession) record(data []byte) {
+	s.outputMux.Lock()
+	defer s.outputMux.Unlock()
+	_, _ = s.scrollback.Write(data)
+	s.broadcast(data)
+	s.recordEvent(s.Recording.Output, data)
+}
+
+// recordEvent writes an event to the recording of the session, if any. A
+// failing recording is only logged once, the session keeps running.
+func (s *BashSession) recordEvent(write func([]byte) error, data []byte) {
+	if s.Recording == nil {
+		return
+	}
+	if err := write(data); err != nil && !errors.Is(err, os.ErrClosed) && s.recordingFailed.CompareAndSwap(false, true) {
+		slog.Error("failed to record session", "session", s.Id, "file", s.Recording.Path(), "error", err)
+	}
+}
+
+// finishOutput closes the output of every attached client and, once the
+// shell was reaped, the session
+func (s *BashSession) finishOutput() {
+	s.outputMux.Lock()
+	if !s.outputDone {
+		s.outputDone = true
+		for _, a := range s.attachments {
+			close(a.output)
+		}
+		s.attachments = nil
+	}
+	s.outputMux.Unlock()
+
+	<-s.exited
+	_ = s.Ptmx.Close()
+	if s.Recording != nil {
+		_ = s.Recording.Close()
+	}
+	s.transition(Closed)
+}
+
+// read reads output from the PTY and records it as session activity
+func (s *BashSession) read(p []byte) (int, error) {
+	n, err := s.Ptmx.Read(p)
+	if n > 0 {
+		s.touch()
+		bytesOut.Add(uint64(n))
+	}
+	return n, err
+}
+
+// Write writes input to the PTY and records it as session activity
+func (s *BashSession) Write(p []byte) (int, error) {
+	if err := s.running(); err != nil {
+		return 0, err
+	}
+	n, err := s.Ptmx.Write(p)
+	if n > 0 {
+		s.touch()
+		bytesIn.Add(uint64(n))
+		s.recordEvent(s.Recording.Input, p[:n])
+	}
+	return n, err
+}
+
+func (s *BashSession) touch() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+// Traffic is how many bytes were written to and read from the PTYs of all
+// sessions so far, including the ones that are gone
+func Traffic() (in, out uint64) {
+	return bytesIn.Load(), bytesOut.Load()
+}
+
+// LastActivity is the last time anything was read from or written to the PTY
+func (s *BashSession) LastActivity() time.Time {
+	return time.Unix(0, s.lastActivity.Load())
+}
+
+// Resize applies the window size reported by the attached client to the PTY,
+// so full-screen programs draw for the client's terminal and not the server's.
+func (s *BashSession) Resize(rows, cols, width, height uint16) error {
+	if err := s.running(); err != nil {
+		return err
+	}
+	err := s.control(func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{
+			Row:    rows,
+			Col:    cols,
+			Xpixel: width,
+			Ypixel: height,
+		})
+	})
+	if err == nil {
+		s.recordEvent(func([]byte) error { return s.Recording.Resize(cols, rows) }, nil)
+	}
+	return err
+}
+
+// Size returns the current window size of the PTY
+func (s *BashSession) Size() (*unix.Winsize, error) {
+	var size *unix.Winsize
+	err := s.control(func(fd int) (err error) {
+		size, err = unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+		return err
+	})
+	return size, err
+}
+
+// foregroundGroup returns the process group that currently owns the terminal
+func (s *BashSession) foregroundGroup() (int, error) {
+	var pgrp int
+	err := s.control(func(fd int) (err error) {
+		pgrp, err = unix.IoctlGetInt(fd, unix.TIOCGPGRP)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get foreground process group: %v", err)
+	}
+	return pgrp, nil
+}
+
+// ForegroundCommand returns the command line of the job in the foreground of
+// the PTY, which is the shell itself when nothing else is running
+func (s *BashSession) ForegroundCommand() (string, error) {
+	pgrp, err := s.foregroundGroup()
+	if err != nil {
+		return "", err
+	}
+
+	// The process group leader has the same ID as the group
+	cmdline, err := os.ReadFile("/proc/" + strconv.Itoa(pgrp) + "/cmdline")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " ")), nil
+}
+
+// control runs f with the raw PTY descriptor. Unlike Ptmx.Fd, it keeps the
+// file in non-blocking mode, so Close still interrupts pending reads.
+func (s *BashSession) control(f func(fd int) error) error {
+	conn, err := s.Ptmx.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var ferr error
+	if err := conn.Control(func(fd uintptr) { ferr = f(int(fd)) }); err != nil {
+		return err
+	}
+	return ferr
+}
+
+// Signal delivers sig to the foreground process group of the PTY, which is
+// whatever job currently owns the terminal, or the shell itself when idle.
+func (s *BashSession) Signal(sig syscall.Signal) error {
+	if err := s.running(); err != nil {
+		return err
+	}
+	pgrp, err := s.foregroundGroup()
+	if err != nil {
+		return err
+	}
+	return unix.Kill(-pgrp, sig)
+}
+
+// Terminate hangs up the shell's process group and kills it if it is still
+// alive after the grace period. It waits for the shell to be reaped, closes the
+// PTY and returns how the shell ended. Terminating a session that is already
+// ending just waits for it.
+func (s *BashSession) Terminate(grace time.Duration) (code int, signal string) {
+	if s.transition(Terminating) {
+		// The shell leads its own session, so its PID is also its process group
+		pgrp := s.TerminalCommand.Process.Pid
+		_ = unix.Kill(-pgrp, unix.SIGHUP)
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		select {
+		case <-s.exited:
+		case <-timer.C:
+			slog.Warn("shell didn't exit after SIGHUP, killing it", "session", s.Id)
+			_ = unix.Kill(-pgrp, unix.SIGKILL)
+		}
+	}
+
+	<-s.exited
+	_ = s.Ptmx.Close()
+	return s.ExitStatus()
+}
+
+// Exited is closed once the shell process has exited and was reaped
+func (s *BashSession) Exited() <-chan struct{} {
+	return s.exited
+}
+
+// ExitStatus reports how the shell ended. It must only be called after
+// Exited is closed.
+func (s *BashSession) ExitStatus() (code int, signal string) {
+	return ProcessExitStatus(s.TerminalCommand.ProcessState)
+}
+
+// ProcessExitStatus extracts the exit code of a finished process. When it was
+// killed by a signal the code follows the shell convention of 128 plus the
+// signal number, and the signal name is returned as well.
+func ProcessExitStatus(state *os.ProcessState) (code int, signal string) {
+	if waitStatus, ok := state.Sys().(syscall.WaitStatus); ok && waitStatus.Signaled() {
+		return 128 + int(waitStatus.Signal()), unix.SignalName(waitStatus.Signal())
+	}
+	return state.ExitCode(), ""
+}
+
+// CheckPTY starts and reaps a trivial command on a new PTY, to find out
+// whether sessions can be spawned at all, e.g. when PTYs or processes run out
+func CheckPTY() error {
+	cmd := exec.Command("true")
+	ptmx, err := pty.Start(cmd)
+	if err != nil {
+		return err
+	}
+	defer ptmx.Close()
+	return cmd.Wait()
 }
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+)
+
+// State is where a session is in its lifecycle. Sessions only move forward,
+// from Running to Closed, and each session moves on its own.
+type State int32
+
+const (
+	Running     State = iota // The shell is alive
+	Terminating              // The shell was hung up and is given time to exit
+	Exited                   // The shell exited and was reaped, its last output may still be read
+	Closed                   // The PTY is closed, nothing is left to read or write
+)
+
+var stateNames = map[State]string{
+	Running:     "RUNNING",
+	Terminating: "TERMINATING",
+	Exited:      "EXITED",
+	Closed:      "CLOSED",
+}
+
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+// ErrSessionEnded is returned when writing to, resizing or signalling a
+// session whose shell is no longer running
+var ErrSessionEnded = errors.New("session has ended")
+
+// transitions lists the states each state may move to
+var transitions = map[State][]State{
+	Running:     {Terminating, Exited},
+	Terminating: {Exited},
+	Exited:      {Closed},
+}
+
+// State returns the current state of the session
+func (s *BashSession) State() State {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+	return s.state
+}
+
+// transition moves the session to the state, and reports whether that is
+// allowed from the current one
+func (s *BashSession) transition(to State) bool {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+
+	for _, allowed := range transitions[s.state] {
+		if allowed == to {
+			s.state = to
+			return true
+		}
+	}
+	return false
+}
+
+// running fails with ErrSessionEnded unless the shell is running
+func (s *BashSession) running() error {
+	if s.State() != Running {
+		return ErrSessionEnded
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package session
+
+import "testing"
+
+func TestTransition(t *testing.T) {
+	for _, test := range []struct {
+		from    State
+		to      State
+		allowed bool
+	}{
+		{Running, Terminating, true},
+		{Running, Exited, true},
+		{Running, Closed, false},
+		{Running, Running, false},
+		{Terminating, Exited, true},
+		{Terminating, Running, false},
+		{Terminating, Closed, false},
+		{Exited, Closed, true},
+		{Exited, Running, false},
+		{Exited, Terminating, false},
+		{Closed, Running, false},
+		{Closed, Exited, false},
+		{Closed, Closed, false},
+	} {
+		bashSession := &BashSession{state: test.from}
+		if got := bashSession.transition(test.to); got != test.allowed {
+			t.Errorf("%v -> %v: transition = %v, want %v", test.from, test.to, got, test.allowed)
+		}
+
+		want := test.from
+		if test.allowed {
+			want = test.to
+		}
+		if got := bashSession.State(); got != want {
+			t.Errorf("%v -> %v: state = %v, want %v", test.from, test.to, got, want)
+		}
+	}
+}
+
+func TestRunning(t *testing.T) {
+	for state, want := range map[State]error{
+		Running:     nil,
+		Terminating: ErrSessionEnded,
+		Exited:      ErrSessionEnded,
+		Closed:      ErrSessionEnded,
+	} {
+		bashSession := &BashSession{state: state}
+		if err := bashSession.running(); err != want {
+			t.Errorf("%v: running() = %v, want %v", state, err, want)
+		}
+		// Nothing reaches the PTY of a session that isn't running
+		if state != Running {
+			if _, err := bashSession.Write([]byte("ls\n")); err != ErrSessionEnded {
+				t.Errorf("%v: Write = %v, want %v", state, err, ErrSessionEnded)
+			}
+		}
+	}
+}
+
+func TestStateString(t *testing.T) {
+	for state, want := range map[State]string{
+		Running:   "RUNNING",
+		Closed:    "CLOSED",
+		State(42): "State(42)",
+	} {
+		if got := state.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
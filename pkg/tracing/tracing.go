@@ -0,0 +1,85 @@
+// Package tracing sets up OpenTelemetry tracing for the client and the
+// server, with the trace context propagated through gRPC metadata
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Options configures where spans are exported to
+type Options struct {
+	ServiceName string
+
+	// OTLP gRPC collector, e.g. localhost:4317. Tracing is disabled when
+	// neither an endpoint nor an exporter is set.
+	Endpoint string
+	Insecure bool // Talk to the collector without TLS
+
+	// Fraction of new traces that are recorded, traces started by the other
+	// side follow its decision
+	SampleRatio float64
+
+	// Replaces the OTLP exporter, e.g. with tracetest.NewInMemoryExporter in tests
+	Exporter sdktrace.SpanExporter
+}
+
+// Setup installs the global tracer provider and propagator. The returned
+// function flushes the spans still buffered and must be called before exiting.
+func Setup(ctx context.Context, options Options) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter := options.Exporter
+	if exporter == nil {
+		if options.Endpoint == "" {
+			return func(context.Context) error { return nil }, nil
+		}
+
+		exporterOptions := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(options.Endpoint)}
+		if options.Insecure {
+			exporterOptions = append(exporterOptions, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, exporterOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(options.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of gSSH, a child of the span in ctx if there is one
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer("gSSH").Start(ctx, name, opts...)
+}
+
+// End ends span, recording err as its status when it isn't nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,121 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"gSSH/pkg/tracing"
+	"gSSH/pkg/tracing/tracingtest"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer starts a span of its own for each check and keeps the
+// metadata the check arrived with
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	incoming metadata.MD
+}
+
+func (h *healthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.incoming, _ = metadata.FromIncomingContext(ctx)
+	_, span := tracing.Start(ctx, "check")
+	tracing.End(span, nil)
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func TestPropagationThroughMetadata(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+
+	listener := bufconn.Listen(1 << 20)
+	handler := &healthServer{}
+	server := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	healthpb.RegisterHealthServer(server, handler)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, root := tracing.Start(context.Background(), "connect")
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	tracing.End(root, err)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	spans := recorder.Spans()
+	connect := tracingtest.Find(t, spans, "connect", trace.SpanKindInternal)
+	clientRPC := tracingtest.Find(t, spans, "grpc.health.v1.Health/Check", trace.SpanKindClient)
+	serverRPC := tracingtest.Find(t, spans, "grpc.health.v1.Health/Check", trace.SpanKindServer)
+	check := tracingtest.Find(t, spans, "check", trace.SpanKindInternal)
+
+	traceparent := handler.incoming.Get("traceparent")
+	if len(traceparent) != 1 {
+		t.Fatalf("traceparent metadata = %q, want one value", traceparent)
+	}
+	want := "00-" + clientRPC.SpanContext.TraceID().String() + "-" + clientRPC.SpanContext.SpanID().String() + "-01"
+	if traceparent[0] != want {
+		t.Errorf("traceparent = %q, want %q", traceparent[0], want)
+	}
+
+	for _, test := range []struct {
+		name   string
+		span   tracetest.SpanStub
+		parent tracetest.SpanStub
+	}{
+		{"client RPC", clientRPC, connect},
+		{"server RPC", serverRPC, clientRPC},
+		{"handler", check, serverRPC},
+	} {
+		if test.span.SpanContext.TraceID() != connect.SpanContext.TraceID() {
+			t.Errorf("%s span is in trace %s, want %s", test.name, test.span.SpanContext.TraceID(), connect.SpanContext.TraceID())
+		}
+		if test.span.Parent.SpanID() != test.parent.SpanContext.SpanID() {
+			t.Errorf("%s span's parent is %s, want %s", test.name, test.span.Parent.SpanID(), test.parent.SpanContext.SpanID())
+		}
+	}
+	if !serverRPC.Parent.IsRemote() {
+		t.Error("server RPC span's parent isn't remote")
+	}
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+
+	_, ok := tracing.Start(context.Background(), "ok")
+	tracing.End(ok, nil)
+	_, failed := tracing.Start(context.Background(), "failed")
+	tracing.End(failed, errors.New("boom"))
+
+	spans := recorder.Spans()
+	if got := tracingtest.Find(t, spans, "ok", trace.SpanKindInternal).Status.Code; got != codes.Unset {
+		t.Errorf("status without an error = %v, want %v", got, codes.Unset)
+	}
+	failedSpan := tracingtest.Find(t, spans, "failed", trace.SpanKindInternal)
+	if failedSpan.Status.Code != codes.Error || failedSpan.Status.Description != "boom" {
+		t.Errorf("status with an error = %v %q, want %v %q", failedSpan.Status.Code, failedSpan.Status.Description, codes.Error, "boom")
+	}
+	if len(failedSpan.Events) != 1 || failedSpan.Events[0].Name != "exception" {
+		t.Errorf("error events = %v, want one exception", failedSpan.Events)
+	}
+}
@@ -0,0 +1,66 @@
+// Package tracingtest records the spans of a test in memory, to check what
+// the code under test traced
+package tracingtest
+
+import (
+	"context"
+	"testing"
+
+	"gSSH/pkg/tracing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recorder keeps every span of the global tracer provider in memory
+type Recorder struct {
+	t        testing.TB
+	exporter *tracetest.InMemoryExporter
+}
+
+// NewRecorder sets up tracing to sample and record every span until the
+// test ends
+func NewRecorder(t testing.TB) *Recorder {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "gSSH-test",
+		SampleRatio: 1,
+		Exporter:    exporter,
+	})
+	if err != nil {
+		t.Fatalf("tracing.Setup: %v", err)
+	}
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+
+	return &Recorder{t: t, exporter: exporter}
+}
+
+// Spans flushes the buffered spans and returns every span ended so far. The
+// exporter forgets them on shutdown, so they are flushed instead.
+func (r *Recorder) Spans() tracetest.SpanStubs {
+	r.t.Helper()
+
+	provider := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		r.t.Fatalf("ForceFlush: %v", err)
+	}
+	return r.exporter.GetSpans()
+}
+
+// Find returns the span with the given name and kind, and fails the test
+// when there is none
+func Find(t testing.TB, spans tracetest.SpanStubs, name string, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name && span.SpanKind == kind {
+			return span
+		}
+	}
+	t.Fatalf("no %v span %q in %d spans", kind, name, len(spans))
+	return tracetest.SpanStub{}
+}